@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// maxPackedArticles bounds how many articles are considered when packing by token budget
+const maxPackedArticles = 100
+
+// summaryLimits are the summary lengths tried, longest first, when packing articles
+var summaryLimits = []int{300, 200, 120, 60}
+
+// packArticles fits as many articles as possible into the input token budget, measured with
+// the Gemini CountTokens API. Summaries are shortened only as much as needed for every article
+// to fit; if they still do not fit at the shortest length, trailing articles are dropped.
+func (c *Client) packArticles(ctx context.Context, newsItems []models.NewsItem, maxNewsItems int, buildPrompt func(maxItems int, articlesJSON string) string) ([]models.NewsItem, error) {
+	if len(newsItems) > maxPackedArticles {
+		newsItems = newsItems[:maxPackedArticles]
+	}
+
+	var packed []models.NewsItem
+	for _, limit := range summaryLimits {
+		packed = trimSummaries(newsItems, limit)
+		tokens, err := c.countPromptTokens(ctx, packed, maxNewsItems, buildPrompt)
+		if err != nil {
+			return nil, err
+		}
+		if tokens <= c.inputTokenBudget {
+			log.Printf("Packed %d articles into %d input tokens (budget %d, summaries up to %d chars)",
+				len(packed), tokens, c.inputTokenBudget, limit)
+			return packed, nil
+		}
+	}
+
+	// Binary search the largest prefix that fits with the shortest summaries
+	low, high := 0, len(packed)
+	for low < high {
+		mid := (low + high + 1) / 2
+		tokens, err := c.countPromptTokens(ctx, packed[:mid], maxNewsItems, buildPrompt)
+		if err != nil {
+			return nil, err
+		}
+		if tokens <= c.inputTokenBudget {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	if low == 0 {
+		return nil, fmt.Errorf("input token budget %d is too small for a single article", c.inputTokenBudget)
+	}
+
+	log.Printf("Packed %d of %d articles into input token budget %d", low, len(packed), c.inputTokenBudget)
+	return packed[:low], nil
+}
+
+// countPromptTokens returns the exact token count of the curation prompt for the articles
+func (c *Client) countPromptTokens(ctx context.Context, newsItems []models.NewsItem, maxNewsItems int, buildPrompt func(maxItems int, articlesJSON string) string) (int, error) {
+	articlesJSON, err := json.MarshalIndent(newsItems, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal news items: %w", err)
+	}
+
+	resp, err := c.model.CountTokens(ctx, genai.Text(buildPrompt(maxNewsItems, string(articlesJSON))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tokens: %w", err)
+	}
+	return int(resp.TotalTokens), nil
+}
+
+// trimSummaries returns a copy of items with summaries cut to at most limit characters
+func trimSummaries(newsItems []models.NewsItem, limit int) []models.NewsItem {
+	trimmed := make([]models.NewsItem, len(newsItems))
+	for i, item := range newsItems {
+		item.Summary = truncate(item.Summary, limit)
+		trimmed[i] = item
+	}
+	return trimmed
+}
+
+// truncate shortens text to at most limit characters, ending with "..." when cut
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-3]) + "..."
+}
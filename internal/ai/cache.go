@@ -0,0 +1,97 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// cacheEntry is a curated response stored for one input set
+type cacheEntry struct {
+	response  models.NewsResponse
+	expiresAt time.Time
+}
+
+// curationCache keeps curated responses keyed by category and a hash of the input articles
+type curationCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+// newCurationCache creates a cache whose entries expire after ttl
+func newCurationCache(ttl time.Duration) *curationCache {
+	return &curationCache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+// get returns a copy of the cached response for key, if present and not expired
+func (c *curationCache) get(key string) (*models.NewsResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return copyResponse(&entry.response), true
+}
+
+// put stores a copy of response under key, dropping expired entries
+func (c *curationCache) put(key string, response *models.NewsResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = cacheEntry{
+		response:  *copyResponse(response),
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// copyResponse copies a response so callers can modify items without touching the cache
+func copyResponse(response *models.NewsResponse) *models.NewsResponse {
+	copied := &models.NewsResponse{
+		News:   make([]models.NewsItem, len(response.News)),
+		Cached: response.Cached,
+	}
+	copy(copied.News, response.News)
+	if response.TokenUsage != nil {
+		usage := *response.TokenUsage
+		copied.TokenUsage = &usage
+	}
+	return copied
+}
+
+// curationKey identifies an input set by category and the sorted article URLs and titles
+func curationKey(categoryName string, newsItems []models.NewsItem) string {
+	entries := make([]string, 0, len(newsItems))
+	for _, item := range newsItems {
+		entries = append(entries, item.URL+"\n"+item.Title)
+	}
+	sort.Strings(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+		h.Write([]byte{0})
+	}
+	return categoryName + ":" + hex.EncodeToString(h.Sum(nil))
+}
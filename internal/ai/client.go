@@ -3,20 +3,23 @@ package ai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
 
 	"github.com/google/generative-ai-go/genai"
+	"github.com/hengky/news-scrapping/internal/category"
 	"github.com/hengky/news-scrapping/pkg/models"
 	"google.golang.org/api/option"
 )
 
 // Client handles Gemini AI operations
 type Client struct {
-	client       *genai.Client
-	model        *genai.GenerativeModel
-	maxNewsItems int
+	client           *genai.Client
+	model            *genai.GenerativeModel
+	maxNewsItems     int
+	inputTokenBudget int // 0 uses the fixed per-category article cap
 }
 
 // New creates a new Gemini AI client
@@ -49,134 +52,183 @@ func NewWithConfig(apiKey string, maxNewsItems int) (*Client, error) {
 	}, nil
 }
 
+// SetSafetySettings sets the harm block thresholds used for every request
+func (c *Client) SetSafetySettings(settings []*genai.SafetySetting) {
+	c.model.SafetySettings = settings
+}
+
+// SetInputTokenBudget sets the maximum prompt size for curation, 0 uses the fixed article cap
+func (c *Client) SetInputTokenBudget(tokens int) {
+	c.inputTokenBudget = tokens
+}
+
 // Close closes the Gemini client
 func (c *Client) Close() error {
 	return c.client.Close()
 }
 
-// ProcessNews processes scraped news and returns top 5 AI tech news (backward compatibility)
-func (c *Client) ProcessNews(newsItems []models.NewsItem) (*models.NewsResponse, error) {
-	return c.ProcessNewsByType(newsItems, "ai")
-}
-
-// ProcessNewsByType processes scraped news based on type and returns top 5
-func (c *Client) ProcessNewsByType(newsItems []models.NewsItem, newsType string) (*models.NewsResponse, error) {
+// ProcessNewsForCategory processes scraped news using the category prompt and returns the top items
+func (c *Client) ProcessNewsForCategory(newsItems []models.NewsItem, cat *category.Category) (*models.NewsResponse, error) {
 	if len(newsItems) == 0 {
 		return &models.NewsResponse{News: []models.NewsItem{}}, nil
 	}
 
 	ctx := context.Background()
 
-	// Limit news items to prevent overwhelming the AI and ensure quality processing
-	var maxArticles int
-	if newsType == "global" {
-		maxArticles = 20 // Global news can handle more with default token limit
-	} else {
-		maxArticles = 15 // AI news - increased for better selection quality with top 10 output
+	// Number of items to select, categories may override the client default
+	maxNewsItems := cat.MaxItems
+	if maxNewsItems <= 0 {
+		maxNewsItems = c.maxNewsItems
 	}
 
-	if len(newsItems) > maxArticles {
-		log.Printf("Limiting news items from %d to %d to prevent token overflow and ensure quality processing", len(newsItems), maxArticles)
-		newsItems = newsItems[:maxArticles]
+	// Fit as many articles as the token budget allows, falling back to the fixed article cap
+	packed := false
+	if c.inputTokenBudget > 0 {
+		items, err := c.packArticles(ctx, newsItems, maxNewsItems, cat.BuildPrompt)
+		if err != nil {
+			log.Printf("Warning: Token budget packing failed, using the %d article cap: %v", cat.MaxArticles, err)
+		} else {
+			newsItems = items
+			packed = true
+		}
+	}
+
+	if !packed {
+		// Limit news items to prevent overwhelming the AI and ensure quality processing
+		maxArticles := cat.MaxArticles
+		if len(newsItems) > maxArticles {
+			log.Printf("Limiting news items from %d to %d to prevent token overflow and ensure quality processing", len(newsItems), maxArticles)
+			newsItems = newsItems[:maxArticles]
+		}
+
+		// Limit summary length for better processing
+		newsItems = trimSummaries(newsItems, 200)
 	}
 
 	// Validate we have sufficient articles for meaningful curation
-	minArticlesRequired := c.maxNewsItems + 2 // Need at least 2 more than output for meaningful selection
+	minArticlesRequired := maxNewsItems + 2 // Need at least 2 more than output for meaningful selection
 	if len(newsItems) < minArticlesRequired {
-		log.Printf("Warning: Only %d articles available for selecting top %d. Consider adjusting news sources or filtering criteria", len(newsItems), c.maxNewsItems)
+		log.Printf("Warning: Only %d articles available for selecting top %d. Consider adjusting news sources or filtering criteria", len(newsItems), maxNewsItems)
 	}
 
-	// Limit summary length for better processing
-	for i := range newsItems {
-		if len(newsItems[i].Summary) > 200 {
-			newsItems[i].Summary = newsItems[i].Summary[:197] + "..."
-		}
-	}
+	return c.curate(ctx, newsItems, maxNewsItems, cat.BuildPrompt)
+}
 
-	// Convert news items to JSON for the prompt
-	articlesJSON, err := json.MarshalIndent(newsItems, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal news items: %w", err)
+// ProcessWeeklyForCategory selects the top stories of the week from the week's digest items
+func (c *Client) ProcessWeeklyForCategory(newsItems []models.NewsItem, cat *category.Category, maxNewsItems int) (*models.NewsResponse, error) {
+	if len(newsItems) == 0 {
+		return &models.NewsResponse{News: []models.NewsItem{}}, nil
 	}
 
-	// Estimate token count (rough approximation: 1 token ≈ 4 characters)
-	estimatedTokens := len(string(articlesJSON)) / 4
-	log.Printf("Estimated input tokens: %d (from %d articles)", estimatedTokens, len(newsItems))
-
-	// Create type-specific optimized prompts
-	var prompt string
-	if newsType == "global" {
-		prompt = fmt.Sprintf(`You are an expert business and technology news curator for a daily Discord newsletter. Select the TOP %d most significant global business, technology, and cryptocurrency developments.
+	log.Printf("Selecting weekly top %d %s stories from %d items", maxNewsItems, cat.Name, len(newsItems))
 
-## EVALUATION CRITERIA (in order of priority):
+	return c.curate(context.Background(), newsItems, maxNewsItems, cat.BuildWeeklyPrompt)
+}
 
-1. **MARKET IMPACT** (40%% weight): Major market movements, IPOs, significant business decisions
-2. **INNOVATION** (25%% weight): New tech products, crypto developments, breakthrough innovations  
-3. **RECENCY** (20%% weight): Prefer articles from last 24-48 hours
-4. **GLOBAL SIGNIFICANCE** (15%% weight): Stories affecting multiple markets or regions
+// curate builds the prompt for the articles and asks Gemini for the top items. When the
+// response is blocked by safety filters, it retries with progressively reduced article sets.
+func (c *Client) curate(ctx context.Context, newsItems []models.NewsItem, maxNewsItems int, buildPrompt func(maxItems int, articlesJSON string) string) (*models.NewsResponse, error) {
+	var lastErr error
+	for attempt, reduce := range blockedFallbacks {
+		items := reduce(newsItems)
+		if len(items) == 0 {
+			break
+		}
+		if attempt > 0 {
+			log.Printf("Retrying curation with a reduced article set (%d articles, attempt %d)", len(items), attempt+1)
+		}
 
-## SELECTION RULES:
-✅ INCLUDE: Reputable publications, official announcements, market-moving news
-❌ EXCLUDE: Duplicates, opinion pieces, unverified rumors, articles >7 days old
+		// Convert news items to JSON for the prompt
+		articlesJSON, err := json.MarshalIndent(items, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal news items: %w", err)
+		}
 
-Return EXACTLY this JSON with %d items ranked by importance:
+		response, err := c.generateNews(ctx, buildPrompt(maxNewsItems, string(articlesJSON)), len(items), maxNewsItems)
+		if err == nil || !errors.Is(err, ErrBlocked) {
+			return response, err
+		}
 
-%s
+		log.Printf("Warning: Gemini blocked the curation response: %v", err)
+		lastErr = err
+	}
 
-{"news":[{"title":"Clear headline (max 100 chars)","summary":"Key facts and implications (max 250 chars)","url":"original_url","source":"publication","relevance":"Why significant (max 100 chars)"}]}`, c.maxNewsItems, string(articlesJSON))
-	} else {
-		// AI-focused optimized prompt
-		prompt = fmt.Sprintf(`You are an expert AI technology news curator for a daily Discord newsletter. Your task is to analyze the provided news articles and select the TOP %d most significant AI technology developments.
+	return nil, lastErr
+}
 
-## EVALUATION CRITERIA (in order of priority):
+// summaryPrompt asks for a short factual summary of a single article's full text
+const summaryPrompt = `You are a news editor. Summarize the article below in 3-5 sentences for a newsletter reader.
 
-1. **IMPACT SIGNIFICANCE** (40%% weight)
-   - Major product launches or updates from leading AI companies
-   - Breakthrough research publications or discoveries
-   - Significant funding rounds or acquisitions in AI
-   - New AI regulations or policy changes
-   - Industry partnerships or collaborations
+## RULES:
+- Lead with what happened, then why it matters
+- Keep key numbers, dates, names, and amounts exactly as written in the article
+- Use only facts stated in the article, no speculation or opinion
+- Write in English, even if the article is in another language
+- Return only the summary as plain text, without headings, bullet points, or markdown
 
-2. **RECENCY & RELEVANCE** (25%% weight)
-   - Prefer articles published within the last 24-48 hours
-   - Breaking news takes priority over older stories
-   - Ongoing developments with new updates
+Headline: %s
+Source: %s
 
-3. **TECHNICAL INNOVATION** (20%% weight)
-   - New AI model architectures or capabilities
-   - Novel applications of existing AI technology
-   - Performance benchmarks or comparisons
-   - Open-source releases or tools
+Article:
+%s`
 
-4. **BUSINESS & MARKET IMPACT** (15%% weight)
-   - Market-moving announcements
-   - Strategic business decisions
-   - Industry adoption trends
+// SummarizeArticle writes a 3-5 sentence summary of an article's extracted full text
+func (c *Client) SummarizeArticle(ctx context.Context, item models.NewsItem, content string) (string, *models.TokenUsage, error) {
+	prompt := fmt.Sprintf(summaryPrompt, item.Title, item.Source, content)
 
-## SELECTION RULES:
-✅ INCLUDE: Reputable tech publications, official company announcements, major AI model updates, regulatory developments
-❌ EXCLUDE: Duplicate stories, opinion pieces without new info, marketing content, unverified rumors, articles >7 days old
+	resp, err := c.model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", nil, wrapBlocked(err)
+	}
 
-## DUPLICATE HANDLING:
-If multiple articles cover the same story, select the most comprehensive and recent version from official sources.
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return "", nil, fmt.Errorf("no candidates returned from Gemini")
+	}
 
-Return EXACTLY this JSON structure with %d items ranked by importance:
+	var tokenUsage *models.TokenUsage
+	if resp.UsageMetadata != nil {
+		tokenUsage = &models.TokenUsage{
+			InputTokens:  resp.UsageMetadata.PromptTokenCount,
+			OutputTokens: resp.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:  resp.UsageMetadata.TotalTokenCount,
+		}
+	}
 
-%s
+	var summary string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if txt, ok := part.(genai.Text); ok {
+			summary += string(txt)
+		}
+	}
 
-{"news":[{"title":"Clear, engaging headline (max 100 chars)","summary":"Concise 2-3 sentence summary focusing on key facts and implications (max 250 chars)","url":"original_article_url","source":"publication_name","relevance":"Brief explanation of why this is significant (max 100 chars)"}]}`, c.maxNewsItems, string(articlesJSON))
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return "", tokenUsage, fmt.Errorf("empty summary from Gemini AI (finish reason: %v)", resp.Candidates[0].FinishReason)
 	}
 
+	return summary, tokenUsage, nil
+}
+
+// generateNews sends the prompt to Gemini and parses the returned news JSON
+func (c *Client) generateNews(ctx context.Context, prompt string, articleCount, maxNewsItems int) (*models.NewsResponse, error) {
 	// Generate content
 	resp, err := c.model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate content: %w", err)
+		return nil, wrapBlocked(err)
 	}
 
 	if len(resp.Candidates) == 0 {
 		return nil, fmt.Errorf("no candidates returned from Gemini")
 	}
 
+	// A candidate without content was filtered before any text was produced
+	if resp.Candidates[0].Content == nil {
+		if isBlockedFinish(resp.Candidates[0].FinishReason) {
+			return nil, fmt.Errorf("%w: candidate finished with %s", ErrBlocked, resp.Candidates[0].FinishReason)
+		}
+		return nil, fmt.Errorf("empty response from Gemini AI (finish reason: %s)", resp.Candidates[0].FinishReason)
+	}
+
 	// Extract token usage
 	var tokenUsage *models.TokenUsage
 	if resp.UsageMetadata != nil {
@@ -203,13 +255,14 @@ Return EXACTLY this JSON structure with %d items ranked by importance:
 	// Check if response is empty
 	if responseText == "" {
 		log.Printf("Empty response from Gemini. Candidate count: %d", len(resp.Candidates))
-		if len(resp.Candidates) > 0 {
-			log.Printf("Candidate finish reason: %v", resp.Candidates[0].FinishReason)
-			if resp.Candidates[0].SafetyRatings != nil {
-				log.Printf("Safety ratings: %v", resp.Candidates[0].SafetyRatings)
-			}
+		log.Printf("Candidate finish reason: %v", resp.Candidates[0].FinishReason)
+		if resp.Candidates[0].SafetyRatings != nil {
+			log.Printf("Safety ratings: %v", resp.Candidates[0].SafetyRatings)
+		}
+		if isBlockedFinish(resp.Candidates[0].FinishReason) {
+			return nil, fmt.Errorf("%w: candidate finished with %s", ErrBlocked, resp.Candidates[0].FinishReason)
 		}
-		return nil, fmt.Errorf("empty response from Gemini AI")
+		return nil, fmt.Errorf("empty response from Gemini AI (finish reason: %s)", resp.Candidates[0].FinishReason)
 	}
 
 	// Remove markdown code blocks if present
@@ -250,14 +303,14 @@ Return EXACTLY this JSON structure with %d items ranked by importance:
 	}
 
 	// Ensure we have at most the configured number of items
-	if len(newsResponse.News) > c.maxNewsItems {
-		newsResponse.News = newsResponse.News[:c.maxNewsItems]
+	if len(newsResponse.News) > maxNewsItems {
+		newsResponse.News = newsResponse.News[:maxNewsItems]
 	}
 
 	// Add token usage to response
 	newsResponse.TokenUsage = tokenUsage
 
-	log.Printf("Gemini processed %d articles and returned %d top news items", len(newsItems), len(newsResponse.News))
+	log.Printf("Gemini processed %d articles and returned %d top news items", articleCount, len(newsResponse.News))
 
 	return &newsResponse, nil
 }
@@ -1,63 +1,269 @@
 package ai
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/hengky/news-scrapping/internal/category"
 	"github.com/hengky/news-scrapping/internal/config"
 	"github.com/hengky/news-scrapping/pkg/models"
 )
 
+// clientDrainTime is how long a replaced Gemini client stays open for requests still using it
+const clientDrainTime = 10 * time.Minute
+
 // Processor handles the complete AI processing pipeline
 type Processor struct {
+	mu     sync.RWMutex
 	client *Client
 	config *config.Config
+	cache  *curationCache // nil disables caching
 }
 
 // NewProcessor creates a new AI processor
 func NewProcessor(cfg *config.Config) (*Processor, error) {
-	client, err := NewWithConfig(cfg.GeminiAPIKey, cfg.MaxNewsItems)
+	client, err := newClient(cfg, cfg.GeminiAPIKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create AI client: %w", err)
+		return nil, err
+	}
+
+	var cache *curationCache
+	if cfg.CurationCacheTTL > 0 {
+		cache = newCurationCache(time.Duration(cfg.CurationCacheTTL) * time.Minute)
 	}
 
 	return &Processor{
 		client: client,
 		config: cfg,
+		cache:  cache,
 	}, nil
 }
 
-// Close closes the AI processor
-func (p *Processor) Close() error {
-	if p.client != nil {
-		return p.client.Close()
+// newClient creates a Gemini client with the configured safety settings and token budget
+func newClient(cfg *config.Config, apiKey string) (*Client, error) {
+	client, err := NewWithConfig(apiKey, cfg.MaxNewsItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AI client: %w", err)
+	}
+
+	safetySettings, err := ParseSafetySettings(cfg.GeminiSafetyThreshold, cfg.GeminiSafetySettings)
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("invalid Gemini safety settings: %w", err)
+	}
+	if len(safetySettings) > 0 {
+		client.SetSafetySettings(safetySettings)
+		log.Printf("Applied %d Gemini safety settings", len(safetySettings))
+	}
+	client.SetInputTokenBudget(cfg.InputTokenBudget)
+
+	return client, nil
+}
+
+// SetConfig applies a reloaded configuration. The Gemini client is replaced when the API key
+// (such as a rotated secret), safety settings, item count, or token budget changed; requests
+// already in flight finish on the previous client, which is closed after clientDrainTime.
+func (p *Processor) SetConfig(cfg *config.Config) error {
+	current := p.currentConfig()
+
+	var client *Client
+	if clientSettingsChanged(current, cfg) {
+		var err error
+		if client, err = newClient(cfg, cfg.GeminiAPIKey); err != nil {
+			return err
+		}
+	}
+
+	p.mu.Lock()
+	previous := p.client
+	if client != nil {
+		p.client = client
+	}
+	if cfg.CurationCacheTTL != current.CurationCacheTTL {
+		p.cache = nil
+		if cfg.CurationCacheTTL > 0 {
+			p.cache = newCurationCache(time.Duration(cfg.CurationCacheTTL) * time.Minute)
+		}
+	}
+	p.config = cfg
+	p.mu.Unlock()
+
+	if client != nil {
+		time.AfterFunc(clientDrainTime, func() {
+			previous.Close()
+		})
+		log.Println("Gemini client updated")
 	}
 	return nil
 }
 
-// ProcessNewsItems processes scraped news and returns curated top 5 (backward compatibility)
-func (p *Processor) ProcessNewsItems(newsItems []models.NewsItem) (*models.NewsResponse, error) {
-	return p.ProcessNewsItemsByType(newsItems, "ai")
+// clientSettingsChanged reports whether the Gemini client must be recreated for the new configuration
+func clientSettingsChanged(current, next *config.Config) bool {
+	return current.GeminiAPIKey != next.GeminiAPIKey ||
+		current.GeminiSafetyThreshold != next.GeminiSafetyThreshold ||
+		current.GeminiSafetySettings != next.GeminiSafetySettings ||
+		current.MaxNewsItems != next.MaxNewsItems ||
+		current.InputTokenBudget != next.InputTokenBudget
 }
 
-// ProcessNewsItemsByType processes scraped news based on type and returns curated top 5
-func (p *Processor) ProcessNewsItemsByType(newsItems []models.NewsItem, newsType string) (*models.NewsResponse, error) {
+// aiClient returns the current Gemini client
+func (p *Processor) aiClient() *Client {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.client
+}
+
+// currentConfig returns the configuration as of the last SetConfig
+func (p *Processor) currentConfig() *config.Config {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config
+}
+
+// curationCache returns the current curation cache, or nil when caching is disabled
+func (p *Processor) curationCache() *curationCache {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cache
+}
+
+// Close closes the AI processor
+func (p *Processor) Close() error {
+	if client := p.aiClient(); client != nil {
+		return client.Close()
+	}
+	return nil
+}
+
+// ProcessNewsItemsForCategory processes scraped news for a category and returns the curated top items
+func (p *Processor) ProcessNewsItemsForCategory(newsItems []models.NewsItem, cat *category.Category) (*models.NewsResponse, error) {
 	if len(newsItems) == 0 {
 		log.Println("No news items to process")
 		return &models.NewsResponse{News: []models.NewsItem{}}, nil
 	}
 
-	log.Printf("Processing %d %s news items with Gemini AI", len(newsItems), newsType)
+	cfg := p.currentConfig()
+	cache := p.curationCache()
 
-	// Process with Gemini AI using type-specific processing
-	response, err := p.client.ProcessNewsByType(newsItems, newsType)
+	// Reuse the curation of an identical article set within the cache TTL
+	var cacheKey string
+	if cache != nil {
+		cacheKey = curationKey(cat.Name, newsItems)
+		if cached, ok := cache.get(cacheKey); ok {
+			log.Printf("Using cached AI curation for %d %s news items", len(newsItems), cat.Name)
+			cached.Cached = true
+			return cached, nil
+		}
+	}
+
+	// Rank articles heuristically so packing and the article cap keep the best candidates
+	if cfg.QualityScoring {
+		newsItems = rankByQuality(newsItems, cat, cfg.QualityMinScore, time.Now())
+		if len(newsItems) == 0 {
+			log.Printf("No %s news items passed quality scoring", cat.Name)
+			return &models.NewsResponse{News: []models.NewsItem{}}, nil
+		}
+	}
+
+	log.Printf("Processing %d %s news items with Gemini AI", len(newsItems), cat.Name)
+
+	// Process with Gemini AI using the category prompt
+	response, err := p.aiClient().ProcessNewsForCategory(newsItems, cat)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process news with AI: %w", err)
 	}
 
+	response.News = validateNews(response.News)
+	response.News = filterByScore(response.News, cfg.MinRelevanceScore)
+
+	log.Printf("AI processing completed: %d valid %s news items selected", len(response.News), cat.Name)
+
+	if cache != nil && len(response.News) > 0 {
+		cache.put(cacheKey, response)
+	}
+
+	return response, nil
+}
+
+// ProcessWeeklyItemsForCategory curates the week's top stories for a category from its daily digests
+func (p *Processor) ProcessWeeklyItemsForCategory(newsItems []models.NewsItem, cat *category.Category, maxNewsItems int) (*models.NewsResponse, error) {
+	if len(newsItems) == 0 {
+		log.Println("No news items to process")
+		return &models.NewsResponse{News: []models.NewsItem{}}, nil
+	}
+
+	response, err := p.aiClient().ProcessWeeklyForCategory(newsItems, cat, maxNewsItems)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process weekly news with AI: %w", err)
+	}
+
+	response.News = validateNews(response.News)
+
+	log.Printf("Weekly AI processing completed: %d valid %s news items selected", len(response.News), cat.Name)
+
+	return response, nil
+}
+
+// ArticleFetcher returns the extracted text of an article page
+type ArticleFetcher func(ctx context.Context, articleURL string) (string, error)
+
+// minDeepSummaryChars is the least extracted text worth summarizing; shorter pages are
+// usually paywalls or cookie walls
+const minDeepSummaryChars = 500
+
+// DeepSummarize replaces each item's summary with one written from the article's full text.
+// Items whose article cannot be fetched or summarized keep their feed summary.
+func (p *Processor) DeepSummarize(ctx context.Context, response *models.NewsResponse, fetch ArticleFetcher) {
+	summarized := 0
+	for i := range response.News {
+		item := &response.News[i]
+
+		content, err := fetch(ctx, item.URL)
+		if err != nil {
+			log.Printf("Warning: Could not fetch article for deep summary, keeping feed summary: %v", err)
+			continue
+		}
+		if len(content) < minDeepSummaryChars {
+			log.Printf("Warning: Only %d chars extracted from %s, keeping feed summary", len(content), item.URL)
+			continue
+		}
+
+		summary, usage, err := p.aiClient().SummarizeArticle(ctx, *item, content)
+		addTokenUsage(response, usage)
+		if err != nil {
+			log.Printf("Warning: Deep summary failed for %s, keeping feed summary: %v", item.URL, err)
+			continue
+		}
+
+		item.Summary = summary
+		summarized++
+	}
+
+	log.Printf("Deep summarization completed: %d of %d items summarized from full text", summarized, len(response.News))
+}
+
+// addTokenUsage adds usage from an extra AI call to the response totals
+func addTokenUsage(response *models.NewsResponse, usage *models.TokenUsage) {
+	if usage == nil {
+		return
+	}
+	if response.TokenUsage == nil {
+		response.TokenUsage = &models.TokenUsage{}
+	}
+	response.TokenUsage.InputTokens += usage.InputTokens
+	response.TokenUsage.OutputTokens += usage.OutputTokens
+	response.TokenUsage.TotalTokens += usage.TotalTokens
+}
+
+// validateNews drops items without a title or URL and fills in missing fields
+func validateNews(news []models.NewsItem) []models.NewsItem {
 	// Validate each news item in response
 	var validNews []models.NewsItem
-	for i, item := range response.News {
+	for i, item := range news {
 		if item.Title == "" {
 			log.Printf("Warning: News item %d has empty title, skipping", i+1)
 			continue
@@ -73,13 +279,44 @@ func (p *Processor) ProcessNewsItemsByType(newsItems []models.NewsItem, newsType
 		if item.Source == "" {
 			item.Source = "Unknown"
 		}
+		item.Sentiment = normalizeLabel(item.Sentiment, models.SentimentPositive, models.SentimentNegative, models.SentimentNeutral)
+		if item.RelevanceScore != nil {
+			score := min(max(*item.RelevanceScore, 0), 100)
+			item.RelevanceScore = &score
+		}
+		item.Impact = normalizeLabel(item.Impact, models.ImpactLow, models.ImpactMedium, models.ImpactHigh)
 
 		validNews = append(validNews, item)
 	}
 
-	response.News = validNews
-	
-	log.Printf("AI processing completed: %d valid %s news items selected", len(response.News), newsType)
+	return validNews
+}
 
-	return response, nil
-}
\ No newline at end of file
+// filterByScore drops items scored below minScore so thin news days are not padded with filler.
+// Items the AI did not score are kept.
+func filterByScore(news []models.NewsItem, minScore int) []models.NewsItem {
+	if minScore <= 0 {
+		return news
+	}
+
+	var kept []models.NewsItem
+	for _, item := range news {
+		if item.RelevanceScore != nil && *item.RelevanceScore < minScore {
+			log.Printf("Dropping %q with relevance score %d below threshold %d", item.Title, *item.RelevanceScore, minScore)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// normalizeLabel lowercases an AI classification label, clearing values outside the allowed set
+func normalizeLabel(value string, allowed ...string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, a := range allowed {
+		if value == a {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// Weights of the heuristic quality signals, summing to 1. The source weight multiplies the total.
+const (
+	recencyWeight   = 0.35
+	keywordWeight   = 0.30
+	titleWeight     = 0.20
+	summaryWeight   = 0.15
+	recencyHalfLife = 12 * time.Hour // An article loses half its recency score every 12 hours
+)
+
+// clickbaitPhrases lower the title score of articles that read like clickbait
+var clickbaitPhrases = []string{
+	"you won't believe", "you wont believe", "shocking", "this one trick", "what happened next",
+	"must see", "goes viral", "will blow your mind", "here's why", "here is why",
+}
+
+// scoredItem is a news item with its heuristic quality score
+type scoredItem struct {
+	item  models.NewsItem
+	score float64
+}
+
+// rankByQuality orders articles by a heuristic quality score, best first, and drops articles
+// scoring below minScore (0-100). Ties keep their original order.
+func rankByQuality(newsItems []models.NewsItem, cat *category.Category, minScore int, now time.Time) []models.NewsItem {
+	sourceWeights := make(map[string]float64, len(cat.Sources))
+	for _, source := range cat.Sources {
+		if source.Weight > 0 {
+			sourceWeights[source.Name] = source.Weight
+		}
+	}
+
+	scored := make([]scoredItem, 0, len(newsItems))
+	for _, item := range newsItems {
+		score := qualityScore(item, cat.Keywords, sourceWeights, now)
+		if score*100 < float64(minScore) {
+			continue
+		}
+		scored = append(scored, scoredItem{item: item, score: score})
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].score > scored[j].score
+	})
+
+	ranked := make([]models.NewsItem, len(scored))
+	for i, s := range scored {
+		ranked[i] = s.item
+	}
+
+	if dropped := len(newsItems) - len(ranked); dropped > 0 {
+		log.Printf("Quality scoring dropped %d of %d %s articles below score %d", dropped, len(newsItems), cat.Name, minScore)
+	}
+	if len(scored) > 0 {
+		log.Printf("Ranked %d %s articles by quality (best %.0f, worst %.0f)",
+			len(scored), cat.Name, scored[0].score*100, scored[len(scored)-1].score*100)
+	}
+
+	return ranked
+}
+
+// qualityScore combines recency, keyword density, title quality, and summary length into a
+// score between 0 and 1, scaled by the weight of the article's source
+func qualityScore(item models.NewsItem, keywords []string, sourceWeights map[string]float64, now time.Time) float64 {
+	score := recencyWeight*recencyScore(item.PublishedAt, now) +
+		keywordWeight*keywordScore(item, keywords) +
+		titleWeight*titleScore(item.Title) +
+		summaryWeight*summaryScore(item.Summary)
+
+	if weight, ok := sourceWeights[item.Source]; ok {
+		score *= weight
+	}
+	return math.Min(score, 1)
+}
+
+// recencyScore decays exponentially with the article's age; undated articles score half
+func recencyScore(publishedAt, now time.Time) float64 {
+	if publishedAt.IsZero() {
+		return 0.5
+	}
+	age := now.Sub(publishedAt)
+	if age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(recencyHalfLife))
+}
+
+// keywordScore rewards articles mentioning the category keywords, counting title matches twice.
+// Categories without keywords score every article the same.
+func keywordScore(item models.NewsItem, keywords []string) float64 {
+	if len(keywords) == 0 {
+		return 0.5
+	}
+
+	title := strings.ToLower(item.Title)
+	summary := strings.ToLower(item.Summary)
+	hits := 0
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(keyword)
+		if keyword == "" {
+			continue
+		}
+		hits += 2 * category.CountKeyword(title, keyword)
+		hits += category.CountKeyword(summary, keyword)
+	}
+
+	// Three matches (or one in the title and one in the summary) is a strong signal
+	return math.Min(float64(hits)/3, 1)
+}
+
+// titleScore prefers informative titles of 6-18 words and penalizes very short titles,
+// shouting, and clickbait
+func titleScore(title string) float64 {
+	words := strings.Fields(title)
+	var score float64
+	switch n := len(words); {
+	case n == 0:
+		return 0
+	case n < 4:
+		score = 0.3
+	case n < 6:
+		score = 0.7
+	case n <= 18:
+		score = 1
+	case n <= 25:
+		score = 0.7
+	default:
+		score = 0.4
+	}
+
+	letters, upper := 0, 0
+	for _, r := range title {
+		if unicode.IsLetter(r) {
+			letters++
+			if unicode.IsUpper(r) {
+				upper++
+			}
+		}
+	}
+	if letters > 10 && float64(upper)/float64(letters) > 0.6 {
+		score *= 0.5
+	}
+
+	if strings.Count(title, "!") > 0 || strings.Count(title, "?") > 1 {
+		score *= 0.8
+	}
+
+	lower := strings.ToLower(title)
+	for _, phrase := range clickbaitPhrases {
+		if strings.Contains(lower, phrase) {
+			score *= 0.5
+			break
+		}
+	}
+
+	return score
+}
+
+// summaryScore grows with the summary length up to 200 characters; teaser-only items score low
+func summaryScore(summary string) float64 {
+	return math.Min(float64(len([]rune(strings.TrimSpace(summary))))/200, 1)
+}
@@ -0,0 +1,118 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// ErrBlocked is returned when Gemini blocks a prompt or response with its safety filters
+var ErrBlocked = errors.New("blocked by Gemini safety filters")
+
+// safetyCategories maps config names to Gemini harm categories
+var safetyCategories = map[string]genai.HarmCategory{
+	"harassment":        genai.HarmCategoryHarassment,
+	"hate_speech":       genai.HarmCategoryHateSpeech,
+	"sexually_explicit": genai.HarmCategorySexuallyExplicit,
+	"dangerous_content": genai.HarmCategoryDangerousContent,
+}
+
+// safetyThresholds maps config names to Gemini block thresholds
+var safetyThresholds = map[string]genai.HarmBlockThreshold{
+	"block_none":             genai.HarmBlockNone,
+	"block_only_high":        genai.HarmBlockOnlyHigh,
+	"block_medium_and_above": genai.HarmBlockMediumAndAbove,
+	"block_low_and_above":    genai.HarmBlockLowAndAbove,
+}
+
+// ParseSafetySettings builds Gemini safety settings from a default threshold applied to every
+// harm category plus comma-separated "category=threshold" overrides. Empty input keeps the
+// Gemini defaults.
+func ParseSafetySettings(threshold, overrides string) ([]*genai.SafetySetting, error) {
+	settings := make(map[genai.HarmCategory]genai.HarmBlockThreshold)
+
+	if threshold = strings.ToLower(strings.TrimSpace(threshold)); threshold != "" {
+		value, ok := safetyThresholds[threshold]
+		if !ok {
+			return nil, fmt.Errorf("unknown Gemini safety threshold %q", threshold)
+		}
+		for _, category := range safetyCategories {
+			settings[category] = value
+		}
+	}
+
+	for _, override := range strings.Split(overrides, ",") {
+		override = strings.TrimSpace(override)
+		if override == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(override, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid Gemini safety setting %q, expected category=threshold", override)
+		}
+		category, ok := safetyCategories[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown Gemini safety category %q", name)
+		}
+		categoryThreshold, ok := safetyThresholds[strings.ToLower(strings.TrimSpace(value))]
+		if !ok {
+			return nil, fmt.Errorf("unknown Gemini safety threshold %q", value)
+		}
+		settings[category] = categoryThreshold
+	}
+
+	var result []*genai.SafetySetting
+	for category, value := range settings {
+		result = append(result, &genai.SafetySetting{Category: category, Threshold: value})
+	}
+	return result, nil
+}
+
+// wrapBlocked marks safety blocks with ErrBlocked so callers can retry with reduced input
+func wrapBlocked(err error) error {
+	var blockedErr *genai.BlockedError
+	if errors.As(err, &blockedErr) {
+		return fmt.Errorf("%w: %v", ErrBlocked, blockedErr)
+	}
+	return fmt.Errorf("failed to generate content: %w", err)
+}
+
+// isBlockedFinish reports whether a candidate stopped because of content filtering. Other finish
+// reasons are not blocks, so retrying with reduced input would not help.
+func isBlockedFinish(reason genai.FinishReason) bool {
+	switch reason {
+	case genai.FinishReasonSafety, genai.FinishReasonRecitation:
+		return true
+	default:
+		return false
+	}
+}
+
+// blockedFallbacks are the article sets tried in order when Gemini blocks a curation.
+// Feed summaries are the most likely trigger, so they are dropped first, then the
+// article set is halved.
+var blockedFallbacks = []func([]models.NewsItem) []models.NewsItem{
+	func(items []models.NewsItem) []models.NewsItem { return items },
+	withoutSummaries,
+	func(items []models.NewsItem) []models.NewsItem {
+		return withoutSummaries(items[:(len(items)+1)/2])
+	},
+}
+
+// withoutSummaries returns a copy of items keeping only titles, URLs, and sources
+func withoutSummaries(items []models.NewsItem) []models.NewsItem {
+	reduced := make([]models.NewsItem, len(items))
+	for i, item := range items {
+		reduced[i] = models.NewsItem{
+			Title:       item.Title,
+			URL:         item.URL,
+			Source:      item.Source,
+			PublishedAt: item.PublishedAt,
+		}
+	}
+	return reduced
+}
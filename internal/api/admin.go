@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// scheduleRequest is the body of a reschedule request
+type scheduleRequest struct {
+	Schedule string `json:"schedule"` // Empty restores the configured schedule
+}
+
+// PauseScheduler stops scheduled runs until resumed
+func (h *Handlers) PauseScheduler(c *gin.Context) {
+	if err := h.scheduler.Pause(); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Message: "Failed to pause scheduler",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Scheduler paused, scheduled runs will be skipped until resumed",
+		Data: gin.H{
+			"paused": true,
+		},
+	})
+}
+
+// ResumeScheduler lets scheduled runs run again
+func (h *Handlers) ResumeScheduler(c *gin.Context) {
+	if err := h.scheduler.Resume(); err != nil {
+		c.JSON(http.StatusInternalServerError, models.APIResponse{
+			Message: "Failed to resume scheduler",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Scheduler resumed",
+		Data: gin.H{
+			"paused": false,
+		},
+	})
+}
+
+// GetOutbox lists digests waiting for a delivery retry and those that permanently failed
+func (h *Handlers) GetOutbox(c *gin.Context) {
+	entries := h.scheduler.Outbox()
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Outbox retrieved successfully",
+		Data: gin.H{
+			"entries": entries,
+			"count":   len(entries),
+		},
+	})
+}
+
+// GetSchedules lists the scheduled jobs with their cron expressions and next run times
+func (h *Handlers) GetSchedules(c *gin.Context) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Schedules retrieved successfully",
+		Data: gin.H{
+			"jobs":   h.scheduler.ScheduledJobs(),
+			"paused": h.scheduler.IsPaused(),
+		},
+	})
+}
+
+// UpdateSchedule changes a scheduled job's cron expression at runtime
+func (h *Handlers) UpdateSchedule(c *gin.Context) {
+	var req scheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid request body",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	job, err := h.scheduler.Reschedule(c.Param("job"), strings.TrimSpace(req.Schedule))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Failed to update schedule",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Schedule updated successfully",
+		Data: gin.H{
+			"job": job,
+		},
+	})
+}
+
+// GetConfig returns the effective configuration with API keys, credentials, and webhook tokens redacted
+func (h *Handlers) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Configuration retrieved successfully",
+		Data: gin.H{
+			"config": h.scheduler.Config().Redacted(),
+		},
+	})
+}
+
+// Reload re-reads the configuration, sources, filter rules, prompts, and schedules without a restart
+func (h *Handlers) Reload(c *gin.Context) {
+	if err := h.scheduler.Reload(); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Failed to reload configuration, keeping the current configuration",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Configuration reloaded successfully",
+		Data: gin.H{
+			"categories": h.scheduler.Categories().Names(),
+			"jobs":       h.scheduler.ScheduledJobs(),
+		},
+	})
+}
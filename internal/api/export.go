@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/export"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// ExportDigest renders a stored digest as Markdown, CSV, or JSON.
+// Without a date the most recent digest of the type is exported.
+func (h *Handlers) ExportDigest(c *gin.Context) {
+	newsType := c.DefaultQuery("type", category.DefaultName)
+	cat, ok := h.scheduler.Categories().Get(newsType)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid type",
+			Error:   fmt.Sprintf("Unknown news type %q", newsType),
+		})
+		return
+	}
+
+	kind := c.DefaultQuery("kind", models.DigestDaily)
+	if kind != models.DigestDaily && kind != models.DigestWeekly {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid kind",
+			Error:   "kind must be daily or weekly",
+		})
+		return
+	}
+
+	date := c.Query("date")
+	if date != "" {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Message: "Invalid date",
+				Error:   "date must be formatted as YYYY-MM-DD",
+			})
+			return
+		}
+	}
+
+	format := c.DefaultQuery("format", export.FormatMarkdown)
+	if !export.ValidFormat(format) {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid format",
+			Error:   "format must be markdown, csv, or json",
+		})
+		return
+	}
+
+	digest, ok := h.scheduler.LatestDigest(cat.Name, kind, date)
+	if !ok {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Message: "Digest not found",
+			Error:   fmt.Sprintf("No %s %s digest stored for %s", kind, cat.Name, dateOrLatest(date)),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", export.Filename(digest, format)))
+
+	switch format {
+	case export.FormatMarkdown:
+		c.Data(http.StatusOK, export.ContentType(format), export.Markdown(digest, cat.DisplayName))
+	case export.FormatCSV:
+		data, err := export.CSV(digest)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.APIResponse{
+				Message: "Failed to export digest",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.Data(http.StatusOK, export.ContentType(format), data)
+	default:
+		c.JSON(http.StatusOK, digest)
+	}
+}
+
+// dateOrLatest describes the requested digest date in error messages
+func dateOrLatest(date string) string {
+	if date == "" {
+		return "any date"
+	}
+	return date
+}
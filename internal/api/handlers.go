@@ -2,32 +2,30 @@ package api
 
 import (
 	"fmt"
+	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/hengky/news-scrapping/internal/ai"
+	"github.com/hengky/news-scrapping/internal/category"
 	"github.com/hengky/news-scrapping/internal/config"
 	"github.com/hengky/news-scrapping/internal/discord"
 	"github.com/hengky/news-scrapping/internal/scheduler"
-	"github.com/hengky/news-scrapping/internal/scraper"
 	"github.com/hengky/news-scrapping/pkg/models"
 )
 
 // Handlers contains all HTTP handlers
 type Handlers struct {
-	config    *config.Config
+	config    *config.Config // Startup configuration; reloadable settings are read from scheduler.Config()
 	scheduler *scheduler.Scheduler
 }
 
 // NewHandlers creates a new handlers instance
-func NewHandlers(cfg *config.Config) *Handlers {
-	// Create scheduler for manual operations
-	schedulerInstance := scheduler.New(cfg)
-
+func NewHandlers(cfg *config.Config, sched *scheduler.Scheduler) *Handlers {
 	return &Handlers{
 		config:    cfg,
-		scheduler: schedulerInstance,
+		scheduler: sched,
 	}
 }
 
@@ -47,32 +45,100 @@ func (h *Handlers) RootHandler(c *gin.Context) {
 		"message": "AI Tech News Scrapping Service",
 		"version": "1.0.0",
 		"endpoints": gin.H{
-			"health":  "/health",
-			"status":  "/api/v1/status",
-			"trigger": "/api/v1/trigger (POST)",
-			"latest":  "/api/v1/latest",
+			"health":     "/health",
+			"status":     "/api/v1/status",
+			"jobs":       "/api/v1/jobs",
+			"categories": "/api/v1/categories",
+			"trigger":    "/api/v1/trigger (POST)",
+			"latest":     "/api/v1/latest",
+			"export":     "/api/v1/export",
+			"search":     "/api/v1/search",
+			"hooks":      "/api/v1/hooks/trigger (POST, signed)",
 		},
 	})
 }
 
-// GetStatus returns the current job status
+// GetStatus returns the status of the most recent job along with the status of every job type
 func (h *Handlers) GetStatus(c *gin.Context) {
-	status := h.scheduler.GetJobStatus()
+	status := models.SchedulerStatus{
+		JobStatus: *h.scheduler.GetJobStatus(),
+		Types:     h.scheduler.GetJobStatuses(),
+	}
 	c.JSON(http.StatusOK, status)
 }
 
+// GetJobs returns a page of persisted job executions, newest first, optionally filtered by type
+func (h *Handlers) GetJobs(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid page",
+			Error:   "page must be a positive integer",
+		})
+		return
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid page_size",
+			Error:   "page_size must be between 1 and 100",
+		})
+		return
+	}
+
+	jobType := c.Query("type")
+	jobs, total := h.scheduler.ListJobs(jobType, page, pageSize)
+	if jobs == nil {
+		jobs = []models.JobRecord{}
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Job history retrieved successfully",
+		Data: gin.H{
+			"jobs":        jobs,
+			"count":       len(jobs),
+			"total":       total,
+			"page":        page,
+			"page_size":   pageSize,
+			"total_pages": (total + pageSize - 1) / pageSize,
+		},
+	})
+}
+
+// GetCategories lists the configured news categories
+func (h *Handlers) GetCategories(c *gin.Context) {
+	var categories []gin.H
+	for _, cat := range h.scheduler.Categories().All() {
+		categories = append(categories, gin.H{
+			"name":         cat.Name,
+			"display_name": cat.DisplayName,
+			"max_items":    cat.MaxItems,
+			"schedule":     cat.Schedule,
+			"sources":      cat.Sources,
+		})
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Categories retrieved successfully",
+		Data: gin.H{
+			"categories": categories,
+			"default":    category.DefaultName,
+		},
+	})
+}
+
 // TriggerNews manually triggers news scraping
 func (h *Handlers) TriggerNews(c *gin.Context) {
 	// Get news type from query parameter
-	newsType := c.DefaultQuery("type", "ai")
-	if newsType != "ai" && newsType != "global" {
-		newsType = "ai" // Default to AI for invalid types
-	}
+	newsType := c.DefaultQuery("type", category.DefaultName)
+	cat := h.scheduler.Categories().Resolve(newsType) // Default to AI for invalid types
+	newsType = cat.Name
 
-	// Check if job is already running
-	if h.scheduler.IsRunning() {
+	// Check if a job of this type is already running
+	if h.scheduler.IsTypeRunning(newsType) {
 		c.JSON(http.StatusTooManyRequests, models.APIResponse{
-			Message: "News job is already running",
+			Message: fmt.Sprintf("%s news job is already running", cat.DisplayName),
 			Error:   "Job in progress",
 		})
 		return
@@ -87,12 +153,7 @@ func (h *Handlers) TriggerNews(c *gin.Context) {
 		}
 	}()
 
-	message := fmt.Sprintf("%s news scraping job triggered successfully", newsType)
-	if newsType == "ai" {
-		message = "AI tech news scraping job triggered successfully"
-	} else {
-		message = "Global tech news scraping job triggered successfully"
-	}
+	message := fmt.Sprintf("%s news scraping job triggered successfully", cat.DisplayName)
 
 	c.JSON(http.StatusOK, models.APIResponse{
 		Message: message,
@@ -103,26 +164,40 @@ func (h *Handlers) TriggerNews(c *gin.Context) {
 	})
 }
 
+// TriggerWeeklyDigest manually triggers the weekly digest for every category
+func (h *Handlers) TriggerWeeklyDigest(c *gin.Context) {
+	if h.scheduler.IsWeeklyDigestRunning() {
+		c.JSON(http.StatusTooManyRequests, models.APIResponse{
+			Message: "Weekly digest job is already running",
+			Error:   "Job in progress",
+		})
+		return
+	}
+
+	go func() {
+		if err := h.scheduler.RunManualWeeklyDigest(); err != nil {
+			log.Printf("Manual weekly digest failed: %v", err)
+		}
+	}()
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "Weekly digest job triggered successfully",
+		Data: gin.H{
+			"triggered_at": time.Now().UTC(),
+		},
+	})
+}
+
 // GetLatestNews gets the latest news without sending to Discord
 func (h *Handlers) GetLatestNews(c *gin.Context) {
 	// Get news type from query parameter
-	newsType := c.DefaultQuery("type", "ai")
-	if newsType != "ai" && newsType != "global" {
-		newsType = "ai" // Default to AI for invalid types
-	}
+	newsType := c.DefaultQuery("type", category.DefaultName)
+	cat := h.scheduler.Categories().Resolve(newsType) // Default to AI for invalid types
+	newsType = cat.Name
 
-	// Create temporary instances for this request
-	scraperInstance := scraper.New()
-	
-	aiProcessor, err := ai.NewProcessor(h.config)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.APIResponse{
-			Message: "Failed to initialize AI processor",
-			Error:   err.Error(),
-		})
-		return
-	}
-	defer aiProcessor.Close()
+	// Share the scheduler's scraper and AI processor so repeat requests hit the curation cache
+	scraperInstance := h.scheduler.Scraper()
+	aiProcessor := h.scheduler.Processor()
 
 	// Step 1: Scrape news with specified type
 	newsItems, err := scraperInstance.ScrapeNewsByType(newsType)
@@ -140,14 +215,14 @@ func (h *Handlers) GetLatestNews(c *gin.Context) {
 			Data: gin.H{
 				"news":         []models.NewsItem{},
 				"generated_at": time.Now().UTC(),
-				"source_count": scraperInstance.GetSourceCount(),
+				"source_count": scraperInstance.GetSourceCountByType(newsType),
 			},
 		})
 		return
 	}
 
 	// Step 2: Process with AI using specified type
-	newsResponse, err := aiProcessor.ProcessNewsItemsByType(newsItems, newsType)
+	newsResponse, err := aiProcessor.ProcessNewsItemsForCategory(newsItems, cat)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Message: "Failed to process news with AI",
@@ -163,7 +238,8 @@ func (h *Handlers) GetLatestNews(c *gin.Context) {
 		"scraped_count":  len(newsItems),
 		"selected_count": len(newsResponse.News),
 		"source_count":   scraperInstance.GetSourceCountByType(newsType),
-		"type":          newsType,
+		"type":           newsType,
+		"cached":         newsResponse.Cached,
 	}
 
 	// Add token usage if available
@@ -172,13 +248,8 @@ func (h *Handlers) GetLatestNews(c *gin.Context) {
 	}
 
 	// Return processed news
-	message := fmt.Sprintf("Latest %s news retrieved successfully", newsType)
-	if newsType == "ai" {
-		message = "Latest AI tech news retrieved successfully"
-	} else {
-		message = "Latest global tech news retrieved successfully"
-	}
-	
+	message := fmt.Sprintf("Latest %s news retrieved successfully", cat.DisplayName)
+
 	c.JSON(http.StatusOK, models.APIResponse{
 		Message: message,
 		Data:    responseData,
@@ -187,8 +258,8 @@ func (h *Handlers) GetLatestNews(c *gin.Context) {
 
 // TestDiscord tests Discord webhook (utility endpoint)
 func (h *Handlers) TestDiscord(c *gin.Context) {
-	discordClient := discord.New(h.config.DiscordWebhook)
-	
+	discordClient := discord.New(h.scheduler.Config().DiscordWebhook)
+
 	if err := discordClient.TestWebhook(); err != nil {
 		c.JSON(http.StatusInternalServerError, models.APIResponse{
 			Message: "Discord webhook test failed",
@@ -203,4 +274,4 @@ func (h *Handlers) TestDiscord(c *gin.Context) {
 			"tested_at": time.Now().UTC(),
 		},
 	})
-}
\ No newline at end of file
+}
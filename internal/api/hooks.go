@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// hookTriggerRequest is the body of a signed trigger request
+type hookTriggerRequest struct {
+	Type   string `json:"type"`   // Category to run, defaults to the default category
+	Weekly bool   `json:"weekly"` // Run the weekly digest instead of the daily job
+}
+
+// HookTrigger starts a job from a signed external request, such as a CI workflow or a hosted cron service
+func (h *Handlers) HookTrigger(c *gin.Context) {
+	var req hookTriggerRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Message: "Invalid request body",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	if req.Weekly {
+		if h.scheduler.IsWeeklyDigestRunning() {
+			c.JSON(http.StatusTooManyRequests, models.APIResponse{
+				Message: "Weekly digest job is already running",
+				Error:   "Job in progress",
+			})
+			return
+		}
+
+		go func() {
+			if err := h.scheduler.RunWeeklyDigest(models.TriggerHook); err != nil {
+				log.Printf("Hook-triggered weekly digest failed: %v", err)
+			}
+		}()
+
+		c.JSON(http.StatusAccepted, models.APIResponse{
+			Message: "Weekly digest job triggered successfully",
+			Data: gin.H{
+				"triggered_at": time.Now().UTC(),
+				"weekly":       true,
+			},
+		})
+		return
+	}
+
+	if req.Type == "" {
+		req.Type = category.DefaultName
+	}
+	cat, ok := h.scheduler.Categories().Get(req.Type)
+	if !ok {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Unknown news type",
+			Error:   fmt.Sprintf("type must be one of: %v", h.scheduler.Categories().Names()),
+		})
+		return
+	}
+
+	if h.scheduler.IsTypeRunning(cat.Name) {
+		c.JSON(http.StatusTooManyRequests, models.APIResponse{
+			Message: fmt.Sprintf("%s news job is already running", cat.DisplayName),
+			Error:   "Job in progress",
+		})
+		return
+	}
+
+	go func() {
+		if err := h.scheduler.RunJobByType(cat.Name, models.TriggerHook); err != nil {
+			log.Printf("Hook-triggered %s job failed: %v", cat.Name, err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, models.APIResponse{
+		Message: fmt.Sprintf("%s news scraping job triggered successfully", cat.DisplayName),
+		Data: gin.H{
+			"triggered_at": time.Now().UTC(),
+			"type":         cat.Name,
+		},
+	})
+}
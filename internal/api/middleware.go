@@ -1,7 +1,21 @@
 package api
 
 import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/hengky/news-scrapping/pkg/models"
 )
 
 // corsMiddleware adds CORS headers
@@ -18,4 +32,165 @@ func corsMiddleware() gin.HandlerFunc {
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// adminAuthMiddleware requires the admin API key in the X-API-Key header or as a Bearer token.
+// Admin endpoints are disabled when no key is configured. The key is read on every request
+// so a reloaded or rotated key takes effect immediately.
+func adminAuthMiddleware(apiKey func() string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := apiKey()
+		if apiKey == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.APIResponse{
+				Message: "Admin API is disabled",
+				Error:   "Set ADMIN_API_KEY to enable admin endpoints",
+			})
+			return
+		}
+
+		key := c.GetHeader("X-API-Key")
+		if key == "" {
+			key = strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.APIResponse{
+				Message: "Unauthorized",
+				Error:   "Invalid or missing API key",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// triggerAuthMiddleware protects the manual trigger endpoints once credentials are configured.
+// With ADMIN_API_KEY or HOOK_SECRET set, triggers require the admin key, leaving systems that only
+// hold the hook secret to the signed hook endpoint. With neither set, triggers stay open.
+func triggerAuthMiddleware(apiKey func() string, hookSecret func() string) gin.HandlerFunc {
+	admin := adminAuthMiddleware(apiKey)
+	return func(c *gin.Context) {
+		if apiKey() == "" {
+			if hookSecret() == "" {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, models.APIResponse{
+				Message: "Manual triggers are disabled",
+				Error:   "Set ADMIN_API_KEY, or use the signed /api/v1/hooks/trigger endpoint",
+			})
+			return
+		}
+		admin(c)
+	}
+}
+
+// Signed hook request headers and limits
+const (
+	hookTimestampHeader = "X-Signature-Timestamp"
+	hookSignatureHeader = "X-Signature"
+	hookSignaturePrefix = "sha256="
+	maxHookBodyBytes    = 64 << 10
+)
+
+// replayCache remembers signatures seen within the tolerance window so a captured
+// request cannot be sent again
+type replayCache struct {
+	mu   sync.Mutex
+	seen map[string]time.Time // Signature to expiry
+}
+
+// add records a signature, reporting false if it was already seen and has not expired
+func (r *replayCache) add(signature string, expires time.Time, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for sig, expiry := range r.seen {
+		if now.After(expiry) {
+			delete(r.seen, sig)
+		}
+	}
+
+	if _, ok := r.seen[signature]; ok {
+		return false
+	}
+	r.seen[signature] = expires
+	return true
+}
+
+// hookSignatureMiddleware requires an HMAC-SHA256 signature of "<timestamp>.<body>" made with the
+// shared secret, sent as X-Signature: sha256=<hex> with the unix timestamp in X-Signature-Timestamp.
+// Requests older than the tolerance and repeated signatures are rejected.
+// Signed hooks are disabled when no secret is configured. Like the admin key, the secret and
+// tolerance are read on every request.
+func hookSignatureMiddleware(settings func() (secret string, tolerance time.Duration)) gin.HandlerFunc {
+	replays := &replayCache{seen: make(map[string]time.Time)}
+
+	unauthorized := func(c *gin.Context, reason string) {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.APIResponse{
+			Message: "Unauthorized",
+			Error:   reason,
+		})
+	}
+
+	return func(c *gin.Context) {
+		secret, tolerance := settings()
+		if secret == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.APIResponse{
+				Message: "Signed hooks are disabled",
+				Error:   "Set HOOK_SECRET to enable signed hook endpoints",
+			})
+			return
+		}
+
+		timestamp, err := strconv.ParseInt(c.GetHeader(hookTimestampHeader), 10, 64)
+		if err != nil {
+			unauthorized(c, "Missing or invalid "+hookTimestampHeader+" header")
+			return
+		}
+		now := time.Now()
+		signedAt := time.Unix(timestamp, 0)
+		if signedAt.Before(now.Add(-tolerance)) || signedAt.After(now.Add(tolerance)) {
+			unauthorized(c, "Request timestamp is outside the allowed window")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxHookBodyBytes+1))
+		if err != nil {
+			unauthorized(c, "Failed to read request body")
+			return
+		}
+		if len(body) > maxHookBodyBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, models.APIResponse{
+				Message: "Request body too large",
+				Error:   fmt.Sprintf("Body must be at most %d bytes", maxHookBodyBytes),
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		signature := strings.TrimPrefix(c.GetHeader(hookSignatureHeader), hookSignaturePrefix)
+		provided, err := hex.DecodeString(signature)
+		if err != nil || signature == "" {
+			unauthorized(c, "Missing or invalid "+hookSignatureHeader+" header")
+			return
+		}
+
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+		mac.Write(body)
+		if !hmac.Equal(provided, mac.Sum(nil)) {
+			unauthorized(c, "Invalid signature")
+			return
+		}
+
+		// Signatures expire with their timestamp, so the cache only needs to cover the window
+		if !replays.add(strings.ToLower(signature), signedAt.Add(tolerance), now) {
+			unauthorized(c, "Request has already been used")
+			return
+		}
+
+		c.Next()
+	}
+}
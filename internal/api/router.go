@@ -1,12 +1,15 @@
 package api
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/hengky/news-scrapping/internal/config"
+	"github.com/hengky/news-scrapping/internal/scheduler"
 )
 
 // SetupRouter sets up the Gin router with all routes
-func SetupRouter(cfg *config.Config) *gin.Engine {
+func SetupRouter(cfg *config.Config, sched *scheduler.Scheduler) *gin.Engine {
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
 
@@ -19,17 +22,56 @@ func SetupRouter(cfg *config.Config) *gin.Engine {
 	router.Use(corsMiddleware())
 
 	// Create handlers
-	handlers := NewHandlers(cfg)
+	handlers := NewHandlers(cfg, sched)
+
+	// Credentials are read from the current configuration so reloads and rotated secrets apply
+	adminKey := func() string {
+		return sched.Config().AdminAPIKey
+	}
+	hookSecret := func() string {
+		return sched.Config().HookSecret
+	}
+	hookSettings := func() (string, time.Duration) {
+		current := sched.Config()
+		return current.HookSecret, time.Duration(current.HookToleranceSeconds) * time.Second
+	}
 
 	// Routes
 	v1 := router.Group("/api/v1")
 	{
 		v1.GET("/health", handlers.HealthCheck)
 		v1.GET("/status", handlers.GetStatus)
-		v1.POST("/trigger", handlers.TriggerNews)
+		v1.GET("/jobs", handlers.GetJobs)
+		v1.GET("/categories", handlers.GetCategories)
+		v1.POST("/trigger", triggerAuthMiddleware(adminKey, hookSecret), handlers.TriggerNews)
+		v1.POST("/trigger/weekly", triggerAuthMiddleware(adminKey, hookSecret), handlers.TriggerWeeklyDigest)
 		v1.GET("/latest", handlers.GetLatestNews)
+		v1.GET("/export", handlers.ExportDigest)
+		v1.GET("/search", handlers.SearchArticles)
 	}
 
+	// Admin routes require ADMIN_API_KEY
+	admin := v1.Group("/admin", adminAuthMiddleware(adminKey))
+	{
+		admin.POST("/pause", handlers.PauseScheduler)
+		admin.POST("/resume", handlers.ResumeScheduler)
+		admin.GET("/schedules", handlers.GetSchedules)
+		admin.PUT("/schedules/:job", handlers.UpdateSchedule)
+		admin.GET("/websub", handlers.GetWebSubSubscriptions)
+		admin.GET("/outbox", handlers.GetOutbox)
+	}
+
+	// Reload and configuration inspection also require ADMIN_API_KEY
+	v1.POST("/reload", adminAuthMiddleware(adminKey), handlers.Reload)
+	v1.GET("/config", adminAuthMiddleware(adminKey), handlers.GetConfig)
+
+	// WebSub hubs verify subscriptions and push new feed content here
+	v1.GET("/websub/callback/:id", handlers.WebSubVerify)
+	v1.POST("/websub/callback/:id", handlers.WebSubNotify)
+
+	// Signed trigger for external systems, requires HOOK_SECRET
+	v1.POST("/hooks/trigger", hookSignatureMiddleware(hookSettings), handlers.HookTrigger)
+
 	// Root health check
 	router.GET("/health", handlers.HealthCheck)
 	router.GET("/", handlers.RootHandler)
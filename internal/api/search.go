@@ -0,0 +1,99 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hengky/news-scrapping/internal/storage"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// maxSearchLimit caps the number of results returned by a search
+const maxSearchLimit = 200
+
+// SearchArticles runs a keyword search across the titles, summaries, and sources of stored articles
+func (h *Handlers) SearchArticles(c *gin.Context) {
+	text := c.Query("q")
+	if len(storage.SearchTerms(text)) == 0 {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Missing query",
+			Error:   "q must contain at least one keyword",
+		})
+		return
+	}
+
+	query := storage.SearchQuery{Text: text}
+
+	if newsType := c.Query("type"); newsType != "" {
+		cat, ok := h.scheduler.Categories().Get(newsType)
+		if !ok {
+			c.JSON(http.StatusBadRequest, models.APIResponse{
+				Message: "Invalid type",
+				Error:   fmt.Sprintf("Unknown news type %q", newsType),
+			})
+			return
+		}
+		query.Category = cat.Name
+	}
+
+	var err error
+	if query.From, err = h.parseSearchTime(c.Query("from"), false); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid from",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if query.To, err = h.parseSearchTime(c.Query("to"), true); err != nil {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid to",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	query.Limit, err = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || query.Limit < 1 || query.Limit > maxSearchLimit {
+		c.JSON(http.StatusBadRequest, models.APIResponse{
+			Message: "Invalid limit",
+			Error:   fmt.Sprintf("limit must be between 1 and %d", maxSearchLimit),
+		})
+		return
+	}
+
+	results, total := h.scheduler.SearchArticles(query)
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: fmt.Sprintf("Found %d matching articles", total),
+		Data: gin.H{
+			"query":   text,
+			"results": results,
+			"count":   len(results),
+			"total":   total,
+		},
+	})
+}
+
+// parseSearchTime parses an RFC 3339 timestamp or a YYYY-MM-DD date in the scheduler timezone.
+// A date used as the upper bound includes the whole day.
+func (h *Handlers) parseSearchTime(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", value, h.scheduler.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q must be a YYYY-MM-DD date or an RFC 3339 timestamp", value)
+	}
+	if endOfDay {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hengky/news-scrapping/internal/websub"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// WebSubVerify answers a hub's verification of a subscribe or unsubscribe request by echoing the challenge
+func (h *Handlers) WebSubVerify(c *gin.Context) {
+	manager := h.scheduler.WebSub()
+	if manager == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	challenge, err := manager.Verify(c.Param("id"), c.Request.URL.Query())
+	if err != nil {
+		log.Printf("Warning: Rejected WebSub verification for %s: %v", c.Param("id"), err)
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	c.String(http.StatusOK, challenge)
+}
+
+// WebSubNotify receives new feed content pushed by a hub and adds it to the article pool.
+// Notifications with an invalid signature are acknowledged but ignored, as WebSub requires.
+func (h *Handlers) WebSubNotify(c *gin.Context) {
+	manager := h.scheduler.WebSub()
+	if manager == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, websub.MaxNotificationBytes+1))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if len(body) > websub.MaxNotificationBytes {
+		c.Status(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	_, err = manager.Notify(c.Param("id"), body, c.GetHeader("X-Hub-Signature"))
+	switch {
+	case errors.Is(err, websub.ErrUnknownSubscription):
+		// 410 tells the hub to stop delivering to this callback
+		c.Status(http.StatusGone)
+		return
+	case err != nil:
+		log.Printf("Warning: Ignored WebSub notification for %s: %v", c.Param("id"), err)
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// GetWebSubSubscriptions lists WebSub subscriptions with their state, without their secrets
+func (h *Handlers) GetWebSubSubscriptions(c *gin.Context) {
+	manager := h.scheduler.WebSub()
+	if manager == nil {
+		c.JSON(http.StatusNotFound, models.APIResponse{
+			Message: "WebSub is disabled",
+			Error:   "Set WEBSUB_CALLBACK_URL to enable WebSub subscriptions",
+		})
+		return
+	}
+
+	subscriptions := manager.Subscriptions()
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	c.JSON(http.StatusOK, models.APIResponse{
+		Message: "WebSub subscriptions retrieved successfully",
+		Data: gin.H{
+			"subscriptions": subscriptions,
+			"count":         len(subscriptions),
+		},
+	})
+}
@@ -0,0 +1,102 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hengky/news-scrapping/internal/config"
+	"github.com/hengky/news-scrapping/internal/export"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// ObjectStore writes objects to a bucket
+type ObjectStore interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+}
+
+// Archiver writes curated digests to object storage under date-based keys
+type Archiver struct {
+	store  ObjectStore
+	prefix string // Key prefix, empty or ending in "/"
+}
+
+// New creates an archiver for an s3://bucket/prefix or gs://bucket/prefix URL
+func New(ctx context.Context, cfg *config.Config) (*Archiver, error) {
+	archiveURL, err := url.Parse(cfg.ArchiveURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive URL: %w", err)
+	}
+
+	bucket := archiveURL.Host
+	prefix := strings.Trim(archiveURL.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	var store ObjectStore
+	switch archiveURL.Scheme {
+	case "s3":
+		store = NewS3(S3Config{
+			Bucket:          bucket,
+			Region:          cfg.ArchiveS3Region,
+			Endpoint:        cfg.ArchiveS3Endpoint,
+			AccessKeyID:     cfg.AWSAccessKeyID,
+			SecretAccessKey: cfg.AWSSecretAccessKey,
+			SessionToken:    cfg.AWSSessionToken,
+		})
+	case "gs":
+		store, err = NewGCS(ctx, bucket)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported archive scheme %q", archiveURL.Scheme)
+	}
+
+	return &Archiver{store: store, prefix: prefix}, nil
+}
+
+// SetCredentials applies rotated AWS credentials to an S3 archive. GCS archives use
+// Application Default Credentials and are unaffected.
+func (a *Archiver) SetCredentials(cfg *config.Config) {
+	if s3, ok := a.store.(*S3Store); ok {
+		s3.SetCredentials(cfg.AWSAccessKeyID, cfg.AWSSecretAccessKey, cfg.AWSSessionToken)
+	}
+}
+
+// ArchiveDigest writes a digest as JSON and as rendered Markdown, returning the keys written
+func (a *Archiver) ArchiveDigest(ctx context.Context, digest models.Digest, displayName string) ([]string, error) {
+	data, err := json.MarshalIndent(digest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode digest: %w", err)
+	}
+
+	base := a.digestKey(digest)
+	objects := []struct {
+		key         string
+		data        []byte
+		contentType string
+	}{
+		{base + ".json", data, export.ContentType(export.FormatJSON)},
+		{base + ".md", export.Markdown(digest, displayName), export.ContentType(export.FormatMarkdown)},
+	}
+
+	var keys []string
+	for _, object := range objects {
+		if err := a.store.Put(ctx, object.key, object.data, object.contentType); err != nil {
+			return keys, fmt.Errorf("failed to archive %s: %w", object.key, err)
+		}
+		keys = append(keys, object.key)
+	}
+
+	return keys, nil
+}
+
+// digestKey returns the key of a digest without extension, e.g. digests/2024/01/10/ai-daily-1704848400000000000
+func (a *Archiver) digestKey(digest models.Digest) string {
+	datePath := strings.ReplaceAll(digest.Date, "-", "/")
+	return fmt.Sprintf("%s%s/%s", a.prefix, datePath, digest.ID)
+}
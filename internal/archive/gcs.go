@@ -0,0 +1,42 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"google.golang.org/api/googleapi"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// GCSStore writes objects to Google Cloud Storage using Application Default Credentials
+type GCSStore struct {
+	service *storage.Service
+	bucket  string
+}
+
+// NewGCS creates a Google Cloud Storage object store
+func NewGCS(ctx context.Context, bucket string) (*GCSStore, error) {
+	service, err := storage.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+	return &GCSStore{service: service, bucket: bucket}, nil
+}
+
+// Put uploads an object
+func (g *GCSStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	object := &storage.Object{
+		Name:        key,
+		ContentType: contentType,
+	}
+
+	_, err := g.service.Objects.Insert(g.bucket, object).
+		Media(bytes.NewReader(data), googleapi.ContentType(contentType)).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return fmt.Errorf("failed to upload to GCS: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package category
+
+import "github.com/hengky/news-scrapping/internal/config"
+
+// builtinCategories returns the categories that ship with the service
+func builtinCategories(cfg *config.Config) []*Category {
+	return []*Category{
+		aiCategory(cfg.DiscordWebhook),
+		globalCategory(cfg.DiscordWebhookGlobal),
+		localCategory(cfg.DiscordWebhookLocal),
+		cryptoCategory(cfg.DiscordWebhookCrypto),
+	}
+}
+
+// aiCategory covers AI-focused tech news
+func aiCategory(webhook string) *Category {
+	return &Category{
+		Name:        "ai",
+		DisplayName: "AI Tech",
+		Header:      "🤖 **Daily AI Tech News**",
+		Color:       0x00D4AA, // Green color for AI news
+		Sources: []Source{
+			{Name: "TechCrunch AI", URL: "https://techcrunch.com/category/artificial-intelligence/feed/", Type: "rss"},
+			{Name: "The Verge AI", URL: "https://www.theverge.com/ai-artificial-intelligence/rss/index.xml", Type: "rss"},
+			{Name: "AI News", URL: "https://artificialintelligence-news.com/feed/", Type: "rss"},
+			{Name: "Bloomberg Technology", URL: "https://feeds.bloomberg.com/technology/news.rss", Type: "rss"},
+		},
+		Keywords: []string{
+			"artificial intelligence", "ai", "machine learning", "ml",
+			"deep learning", "neural network", "chatgpt", "openai",
+			"google ai", "microsoft ai", "anthropic", "claude",
+			"generative ai", "llm", "large language model",
+			"computer vision", "natural language processing", "nlp",
+			"automation", "robotics", "algorithm", "data science",
+			"tensorflow", "pytorch", "hugging face",
+		},
+		MaxItemsPerSource: 12,
+		MaxArticles:       15, // Increased for better selection quality with top 10 output
+		Prompt:            aiPrompt,
+		Webhook:           webhook,
+	}
+}
+
+// globalCategory covers global tech/business/markets news
+func globalCategory(webhook string) *Category {
+	return &Category{
+		Name:        "global",
+		DisplayName: "Global Tech",
+		Header:      "🌍 **Daily Global Tech News**",
+		Color:       0x1E88E5, // Blue color for global news
+		Sources: []Source{
+			{Name: "Bloomberg Technology", URL: "https://feeds.bloomberg.com/technology/news.rss", Type: "rss"},
+			{Name: "Bloomberg Crypto", URL: "https://feeds.bloomberg.com/crypto/news.rss", Type: "rss"},
+			{Name: "Bloomberg Politics", URL: "https://feeds.bloomberg.com/politics/news.rss", Type: "rss"},
+			{Name: "Bloomberg Economics", URL: "https://feeds.bloomberg.com/politics/news.rss", Type: "rss"},
+		},
+		Keywords: []string{
+			// Markets & Economics
+			"stock", "market", "nasdaq", "dow", "s&p", "earnings", "revenue", "profit",
+			"economy", "inflation", "recession", "gdp", "interest rate", "federal reserve",
+			"economic", "fiscal", "monetary", "trade war", "tariff",
+
+			// Crypto & Finance
+			"bitcoin", "ethereum", "crypto", "cryptocurrency", "blockchain", "defi",
+			"fintech", "banking", "payment", "financial services", "lending",
+
+			// Business & Corporate
+			"merger", "acquisition", "ipo", "funding", "investment", "venture capital",
+			"startup", "unicorn", "valuation", "ceo", "executive", "leadership",
+			"corporate", "business strategy", "partnerships",
+
+			// Politics & Policy (tech related)
+			"regulation", "policy", "government", "senate", "congress", "biden",
+			"trump", "china", "trade", "sanctions", "antitrust", "monopoly",
+
+			// Major Companies (when in business context)
+			"apple", "microsoft", "google", "amazon", "meta", "tesla", "nvidia",
+			"samsung", "tsmc", "intel", "amd",
+		},
+		MaxItemsPerSource: 8,
+		MaxArticles:       20,
+		Prompt:            globalPrompt,
+		Webhook:           webhook,
+	}
+}
+
+// localCategory covers Indonesian tech/business news. Keywords include both
+// Indonesian and English terms since local outlets publish in both.
+func localCategory(webhook string) *Category {
+	return &Category{
+		Name:        "local",
+		DisplayName: "Indonesia Tech & Business",
+		Header:      "🇮🇩 **Daily Indonesia Tech & Business News**",
+		Color:       0xE53935, // Red color for local news
+		Sources: []Source{
+			{Name: "Katadata", URL: "https://katadata.co.id/rss", Type: "rss"},
+			{Name: "DailySocial", URL: "https://dailysocial.id/feed", Type: "rss"},
+			{Name: "CNBC Indonesia Tech", URL: "https://www.cnbcindonesia.com/tech/rss", Type: "rss"},
+			{Name: "Kontan Keuangan", URL: "https://rss.kontan.co.id/news/keuangan", Type: "rss"},
+		},
+		Keywords: []string{
+			// Tech & startups
+			"startup", "teknologi", "technology", "digital", "aplikasi", "e-commerce",
+			"fintech", "unicorn", "decacorn", "pendanaan", "funding", "investasi",
+			"gojek", "goto", "tokopedia", "grab", "bukalapak", "traveloka", "shopee",
+			"kecerdasan buatan", "artificial intelligence", "ai", "data center",
+			"telkom", "telkomsel", "indosat", "xl axiata", "internet",
+
+			// Economy & markets
+			"ekonomi", "economy", "bisnis", "business", "saham", "stock", "ihsg",
+			"bursa", "idx", "rupiah", "inflasi", "inflation", "suku bunga",
+			"bank indonesia", "ojk", "ipo", "kripto", "crypto", "bitcoin",
+
+			// Policy & regulation
+			"regulasi", "regulation", "kebijakan", "policy", "pemerintah",
+			"kominfo", "komdigi", "kemenkeu", "pajak", "tax",
+		},
+		MaxItemsPerSource: 8,
+		MaxArticles:       20,
+		Prompt:            localPrompt,
+		Webhook:           webhook,
+	}
+}
+
+// cryptoCategory covers cryptocurrency markets, projects, and regulation on its own schedule
+func cryptoCategory(webhook string) *Category {
+	return &Category{
+		Name:        "crypto",
+		DisplayName: "Crypto",
+		Header:      "🪙 **Daily Crypto News**",
+		Color:       0xF7931A, // Bitcoin orange for crypto news
+		Sources: []Source{
+			{Name: "CoinDesk", URL: "https://www.coindesk.com/arc/outboundfeeds/rss/", Type: "rss"},
+			{Name: "The Block", URL: "https://www.theblock.co/rss.xml", Type: "rss"},
+			{Name: "Bloomberg Crypto", URL: "https://feeds.bloomberg.com/crypto/news.rss", Type: "rss"},
+			{Name: "Decrypt", URL: "https://decrypt.co/feed", Type: "rss"},
+		},
+		Keywords: []string{
+			// Assets & markets
+			"bitcoin", "btc", "ethereum", "eth", "solana", "xrp", "stablecoin", "usdt", "usdc",
+			"crypto", "cryptocurrency", "token", "altcoin", "memecoin", "market cap",
+			"etf", "halving", "liquidation", "rally", "sell-off",
+
+			// Infrastructure & DeFi
+			"blockchain", "defi", "decentralized finance", "layer 2", "rollup", "staking",
+			"exchange", "binance", "coinbase", "kraken", "wallet", "nft", "web3", "dao",
+
+			// Regulation & enforcement
+			"sec", "cftc", "regulation", "regulator", "mica", "lawsuit", "enforcement",
+			"sanctions", "hack", "exploit", "stolen",
+		},
+		MaxItemsPerSource: 10,
+		MaxArticles:       20,
+		Prompt:            cryptoPrompt,
+		Webhook:           webhook,
+		Schedule:          "0 7 * * *", // Override with CATEGORY_CRYPTO_SCHEDULE
+	}
+}
+
+// aiPrompt is the AI-focused curation prompt
+const aiPrompt = `You are an expert AI technology news curator for a daily Discord newsletter. Your task is to analyze the provided news articles and select the TOP {{max_items}} most significant AI technology developments.
+
+## EVALUATION CRITERIA (in order of priority):
+
+1. **IMPACT SIGNIFICANCE** (40% weight)
+   - Major product launches or updates from leading AI companies
+   - Breakthrough research publications or discoveries
+   - Significant funding rounds or acquisitions in AI
+   - New AI regulations or policy changes
+   - Industry partnerships or collaborations
+
+2. **RECENCY & RELEVANCE** (25% weight)
+   - Prefer articles published within the last 24-48 hours
+   - Breaking news takes priority over older stories
+   - Ongoing developments with new updates
+
+3. **TECHNICAL INNOVATION** (20% weight)
+   - New AI model architectures or capabilities
+   - Novel applications of existing AI technology
+   - Performance benchmarks or comparisons
+   - Open-source releases or tools
+
+4. **BUSINESS & MARKET IMPACT** (15% weight)
+   - Market-moving announcements
+   - Strategic business decisions
+   - Industry adoption trends
+
+## SELECTION RULES:
+✅ INCLUDE: Reputable tech publications, official company announcements, major AI model updates, regulatory developments
+❌ EXCLUDE: Duplicate stories, opinion pieces without new info, marketing content, unverified rumors, articles >7 days old
+
+## DUPLICATE HANDLING:
+If multiple articles cover the same story, select the most comprehensive and recent version from official sources.
+
+## CLASSIFICATION:
+Tag each item with its sentiment (positive, negative, or neutral tone of the development for readers) and impact (low, medium, or high expected consequence).
+Give each item a relevance_score from 0 to 100 using the criteria above, where 90+ is a must-read and below 50 is filler. Score honestly: on a slow news day, low scores are expected.
+
+Return EXACTLY this JSON structure with {{max_items}} items ranked by importance:
+
+{{articles}}
+
+{"news":[{"title":"Clear, engaging headline (max 100 chars)","summary":"Concise 2-3 sentence summary focusing on key facts and implications (max 250 chars)","url":"original_article_url","source":"publication_name","relevance":"Brief explanation of why this is significant (max 100 chars)","sentiment":"positive|negative|neutral","impact":"low|medium|high","relevance_score":85}]}`
+
+// globalPrompt is the global business/markets curation prompt
+const globalPrompt = `You are an expert business and technology news curator for a daily Discord newsletter. Select the TOP {{max_items}} most significant global business, technology, and cryptocurrency developments.
+
+## EVALUATION CRITERIA (in order of priority):
+
+1. **MARKET IMPACT** (40% weight): Major market movements, IPOs, significant business decisions
+2. **INNOVATION** (25% weight): New tech products, crypto developments, breakthrough innovations  
+3. **RECENCY** (20% weight): Prefer articles from last 24-48 hours
+4. **GLOBAL SIGNIFICANCE** (15% weight): Stories affecting multiple markets or regions
+
+## SELECTION RULES:
+✅ INCLUDE: Reputable publications, official announcements, market-moving news
+❌ EXCLUDE: Duplicates, opinion pieces, unverified rumors, articles >7 days old
+
+## CLASSIFICATION:
+Tag each item with its sentiment (positive, negative, or neutral tone of the development for readers) and impact (low, medium, or high expected consequence).
+Give each item a relevance_score from 0 to 100 using the criteria above, where 90+ is a must-read and below 50 is filler. Score honestly: on a slow news day, low scores are expected.
+
+Return EXACTLY this JSON with {{max_items}} items ranked by importance:
+
+{{articles}}
+
+{"news":[{"title":"Clear headline (max 100 chars)","summary":"Key facts and implications (max 250 chars)","url":"original_url","source":"publication","relevance":"Why significant (max 100 chars)","sentiment":"positive|negative|neutral","impact":"low|medium|high","relevance_score":85}]}`
+
+// localPrompt is the Indonesia-focused curation prompt; articles may be in Bahasa Indonesia
+const localPrompt = `You are an expert Indonesian technology and business news curator for a daily Discord newsletter. Select the TOP {{max_items}} most significant developments in Indonesia's tech, startup, and business landscape.
+
+## EVALUATION CRITERIA (in order of priority):
+
+1. **NATIONAL IMPACT** (40% weight): Policy and regulation (OJK, Bank Indonesia, Komdigi), major funding rounds, IPOs on IDX, moves by leading Indonesian companies
+2. **DIGITAL ECONOMY** (25% weight): Startups, e-commerce, fintech, telco, and AI adoption in Indonesia and Southeast Asia
+3. **RECENCY** (20% weight): Prefer articles from last 24-48 hours
+4. **MARKET RELEVANCE** (15% weight): Rupiah, IHSG, and macroeconomic news affecting Indonesian businesses
+
+## SELECTION RULES:
+✅ INCLUDE: Reputable Indonesian publications, official announcements, stories with clear local impact
+❌ EXCLUDE: Duplicates, celebrity or lifestyle content, opinion pieces, unverified rumors, articles >7 days old
+
+## LANGUAGE:
+Articles may be written in Bahasa Indonesia. Always write the title, summary, and relevance in English, keeping company and proper names unchanged.
+
+## CLASSIFICATION:
+Tag each item with its sentiment (positive, negative, or neutral tone of the development for readers) and impact (low, medium, or high expected consequence).
+Give each item a relevance_score from 0 to 100 using the criteria above, where 90+ is a must-read and below 50 is filler. Score honestly: on a slow news day, low scores are expected.
+
+Return EXACTLY this JSON with {{max_items}} items ranked by importance:
+
+{{articles}}
+
+{"news":[{"title":"Clear English headline (max 100 chars)","summary":"Key facts and implications in English (max 250 chars)","url":"original_url","source":"publication","relevance":"Why significant for Indonesia (max 100 chars)","sentiment":"positive|negative|neutral","impact":"low|medium|high","relevance_score":85}]}`
+
+// cryptoPrompt is the crypto-focused curation prompt emphasizing market impact and regulation
+const cryptoPrompt = `You are an expert cryptocurrency markets and policy news curator for a daily Discord newsletter. Select the TOP {{max_items}} most significant crypto developments.
+
+## EVALUATION CRITERIA (in order of priority):
+
+1. **MARKET IMPACT** (40% weight): Large price moves in major assets, ETF flows and approvals, exchange listings or failures, liquidations, institutional adoption
+2. **REGULATION & ENFORCEMENT** (30% weight): SEC/CFTC actions, MiCA and other national frameworks, court rulings, sanctions, exchange licensing
+3. **SECURITY & INFRASTRUCTURE** (15% weight): Major hacks and exploits, protocol upgrades, stablecoin developments
+4. **RECENCY** (15% weight): Prefer articles from last 24-48 hours
+
+## SELECTION RULES:
+✅ INCLUDE: Reputable crypto and financial publications, official announcements, on-chain facts with clear figures
+❌ EXCLUDE: Price predictions, token promotions, sponsored content, duplicates, unverified rumors, articles >7 days old
+
+## DUPLICATE HANDLING:
+If multiple articles cover the same story, select the most comprehensive and recent version.
+
+## CLASSIFICATION:
+Tag each item with its sentiment (positive, negative, or neutral tone of the development for readers) and impact (low, medium, or high expected consequence).
+Give each item a relevance_score from 0 to 100 using the criteria above, where 90+ is a must-read and below 50 is filler. Score honestly: on a slow news day, low scores are expected.
+
+Return EXACTLY this JSON with {{max_items}} items ranked by importance:
+
+{{articles}}
+
+{"news":[{"title":"Clear headline (max 100 chars)","summary":"Key facts, figures, and market implications (max 250 chars)","url":"original_url","source":"publication","relevance":"Why it matters for markets or regulation (max 100 chars)","sentiment":"positive|negative|neutral","impact":"low|medium|high","relevance_score":85}]}`
@@ -0,0 +1,467 @@
+package category
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hengky/news-scrapping/internal/config"
+)
+
+// shortKeywordLen is the length up to which keywords, usually acronyms such as "ai" or "ipo",
+// must match a whole word
+const shortKeywordLen = 3
+
+// DefaultName is the category used when none (or an unknown one) is requested
+const DefaultName = "ai"
+
+// Prompt placeholders replaced when building the AI prompt
+const (
+	PlaceholderMaxItems = "{{max_items}}"
+	PlaceholderArticles = "{{articles}}"
+)
+
+// Source represents a news source configuration
+type Source struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	Type string `json:"type"` // "rss" or "web"
+	// Weight scales the quality score of the source's articles when ranking them before
+	// curation, e.g. 1.5 for a trusted source or 0.5 for a noisy one. 0 means 1.
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// Category describes everything needed to scrape, curate, and deliver one kind of news
+type Category struct {
+	Name              string   `json:"name"`
+	DisplayName       string   `json:"display_name"`
+	Header            string   `json:"header"` // Discord message header, the date is appended
+	Color             int      `json:"color"`  // Discord embed color as a decimal RGB value
+	Sources           []Source `json:"sources"`
+	Keywords          []string `json:"keywords"`  // Empty keeps every recent item
+	MaxItems          int      `json:"max_items"` // Items selected by the AI, defaults to MAX_NEWS_ITEMS
+	MaxItemsPerSource int      `json:"max_items_per_source"`
+	MaxArticles       int      `json:"max_articles"` // Cap on articles sent to the AI
+	Prompt            string   `json:"prompt"`
+	WeeklyPrompt      string   `json:"weekly_prompt"` // Empty uses the generic weekly retrospective prompt
+	Webhook           string   `json:"webhook"`
+	Schedule          string   `json:"schedule"` // Cron expression; empty runs with the daily job
+	Template          string   `json:"template"` // Name of a TEMPLATE_DIR template; empty uses the default embeds
+
+	tmpl *template.Template
+}
+
+// Matches reports whether content passes the category keyword filter
+func (c *Category) Matches(content string) bool {
+	if len(c.Keywords) == 0 {
+		return true
+	}
+
+	content = strings.ToLower(content)
+	for _, keyword := range c.Keywords {
+		if CountKeyword(content, strings.ToLower(keyword)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CountKeyword counts the occurrences of a lowercase keyword in lowercase content that start at
+// a word boundary, so longer keywords also match inflections ("startup" in "startups"). Keywords
+// of up to three letters must match a whole word, so "ai" does not match "said" or "pakai".
+func CountKeyword(content, keyword string) int {
+	if keyword == "" {
+		return 0
+	}
+	wholeWord := utf8.RuneCountInString(keyword) <= shortKeywordLen
+
+	count := 0
+	for offset := 0; offset < len(content); {
+		i := strings.Index(content[offset:], keyword)
+		if i < 0 {
+			break
+		}
+		start := offset + i
+		end := start + len(keyword)
+		offset = end
+
+		before, _ := utf8.DecodeLastRuneInString(content[:start])
+		if start > 0 && isWordRune(before) {
+			continue
+		}
+		after, _ := utf8.DecodeRuneInString(content[end:])
+		if wholeWord && end < len(content) && isWordRune(after) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// BuildPrompt fills the prompt template with the item count and the articles JSON
+func (c *Category) BuildPrompt(maxItems int, articlesJSON string) string {
+	prompt := c.Prompt
+	if prompt == "" {
+		prompt = genericPrompt(c.DisplayName)
+	}
+
+	return strings.NewReplacer(
+		PlaceholderMaxItems, strconv.Itoa(maxItems),
+		PlaceholderArticles, articlesJSON,
+	).Replace(prompt)
+}
+
+// BuildWeeklyPrompt fills the weekly retrospective prompt with the item count and the week's items
+func (c *Category) BuildWeeklyPrompt(maxItems int, articlesJSON string) string {
+	prompt := c.WeeklyPrompt
+	if prompt == "" {
+		prompt = weeklyPrompt(c.DisplayName)
+	}
+
+	return strings.NewReplacer(
+		PlaceholderMaxItems, strconv.Itoa(maxItems),
+		PlaceholderArticles, articlesJSON,
+	).Replace(prompt)
+}
+
+// mergeFrom overrides fields of c with the non-zero fields of other
+func (c *Category) mergeFrom(other *Category) {
+	if other.DisplayName != "" {
+		c.DisplayName = other.DisplayName
+	}
+	if other.Header != "" {
+		c.Header = other.Header
+	}
+	if other.Color != 0 {
+		c.Color = other.Color
+	}
+	if len(other.Sources) > 0 {
+		c.Sources = other.Sources
+	}
+	if len(other.Keywords) > 0 {
+		c.Keywords = other.Keywords
+	}
+	if other.MaxItems > 0 {
+		c.MaxItems = other.MaxItems
+	}
+	if other.MaxItemsPerSource > 0 {
+		c.MaxItemsPerSource = other.MaxItemsPerSource
+	}
+	if other.MaxArticles > 0 {
+		c.MaxArticles = other.MaxArticles
+	}
+	if other.Prompt != "" {
+		c.Prompt = other.Prompt
+	}
+	if other.WeeklyPrompt != "" {
+		c.WeeklyPrompt = other.WeeklyPrompt
+	}
+	if other.Webhook != "" {
+		c.Webhook = other.Webhook
+	}
+	if other.Schedule != "" {
+		c.Schedule = other.Schedule
+	}
+	if other.Template != "" {
+		c.Template = other.Template
+	}
+}
+
+// applyOverride applies CATEGORY_<NAME>_* settings from the environment
+func (c *Category) applyOverride(override config.CategoryOverride) error {
+	if override.MaxItems > 0 {
+		c.MaxItems = override.MaxItems
+	}
+	if override.Schedule != "" {
+		c.Schedule = override.Schedule
+	}
+	if override.Webhook != "" {
+		c.Webhook = override.Webhook
+	}
+	if override.Template != "" {
+		c.Template = override.Template
+	}
+	if override.PromptFile != "" {
+		prompt, err := os.ReadFile(override.PromptFile)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt file for category %q: %w", c.Name, err)
+		}
+		c.Prompt = string(prompt)
+	}
+	return nil
+}
+
+// applyDefaults fills in fields that were left empty
+func (c *Category) applyDefaults(cfg *config.Config) {
+	if c.DisplayName == "" {
+		c.DisplayName = strings.ToUpper(c.Name[:1]) + c.Name[1:]
+	}
+	if c.Header == "" {
+		c.Header = fmt.Sprintf("📰 **Daily %s News**", c.DisplayName)
+	}
+	if c.Color == 0 {
+		c.Color = 0x7289DA
+	}
+	if c.MaxItems <= 0 {
+		c.MaxItems = cfg.MaxNewsItems
+	}
+	if c.MaxItemsPerSource <= 0 {
+		c.MaxItemsPerSource = 8
+	}
+	if c.MaxArticles <= 0 {
+		c.MaxArticles = 20
+	}
+	if c.Webhook == "" {
+		c.Webhook = cfg.DiscordWebhook
+	}
+	if c.Template == "" {
+		c.Template = cfg.DigestTemplate
+	}
+}
+
+// applyTemplate looks up the category's digest template
+func (c *Category) applyTemplate(templates map[string]*template.Template) error {
+	if c.Template == "" {
+		return nil
+	}
+	tmpl, ok := templates[c.Template]
+	if !ok {
+		return fmt.Errorf("category %q uses unknown template %q (no %s%s in TEMPLATE_DIR)", c.Name, c.Template, c.Template, templateExt)
+	}
+	c.tmpl = tmpl
+	return nil
+}
+
+// Registry holds the configured news categories. Its contents can be swapped at runtime by Replace;
+// categories themselves are never modified once registered.
+type Registry struct {
+	mu         sync.RWMutex
+	categories map[string]*Category
+	order      []string
+}
+
+// NewRegistry creates a registry with the built-in categories plus any defined in CATEGORIES_FILE.
+// File entries with a built-in name override that category's non-empty fields, and
+// CATEGORY_<NAME>_* environment variables override both.
+func NewRegistry(cfg *config.Config) (*Registry, error) {
+	r := &Registry{
+		categories: make(map[string]*Category),
+	}
+
+	for _, cat := range builtinCategories(cfg) {
+		r.add(cat)
+	}
+
+	if cfg.CategoriesFile != "" {
+		fileCategories, err := loadFile(cfg.CategoriesFile)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cat := range fileCategories {
+			if existing, ok := r.categories[cat.Name]; ok {
+				existing.mergeFrom(cat)
+				continue
+			}
+			if len(cat.Sources) == 0 {
+				return nil, fmt.Errorf("category %q in %s has no sources", cat.Name, cfg.CategoriesFile)
+			}
+			r.add(cat)
+		}
+		log.Printf("Loaded %d categories from %s", len(fileCategories), cfg.CategoriesFile)
+	}
+
+	for name, override := range cfg.CategoryOverrides {
+		cat, ok := r.categories[name]
+		if !ok {
+			log.Printf("Warning: Ignoring CATEGORY_%s_* settings for unknown category %q", strings.ToUpper(name), name)
+			continue
+		}
+		if err := cat.applyOverride(override); err != nil {
+			return nil, err
+		}
+	}
+
+	templates, err := loadTemplates(cfg.TemplateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cat := range r.categories {
+		cat.applyDefaults(cfg)
+		if err := cat.applyTemplate(templates); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// add registers a category, keeping registration order
+func (r *Registry) add(cat *Category) {
+	if _, exists := r.categories[cat.Name]; !exists {
+		r.order = append(r.order, cat.Name)
+	}
+	r.categories[cat.Name] = cat
+}
+
+// Replace swaps in the categories of another registry, used when reloading configuration.
+// Callers holding a category from before the swap keep using it until they finish.
+func (r *Registry) Replace(other *Registry) {
+	other.mu.RLock()
+	categories := other.categories
+	order := other.order
+	other.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.categories = categories
+	r.order = order
+}
+
+// ReplaceWebhooks swaps rotated webhook URLs, keyed by the URL they replace, into the registered
+// categories. Affected categories are copied, so callers holding one keep its old webhook.
+func (r *Registry) ReplaceWebhooks(rotated map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, cat := range r.categories {
+		if webhook, ok := rotated[cat.Webhook]; ok {
+			updated := *cat
+			updated.Webhook = webhook
+			r.categories[name] = &updated
+		}
+	}
+}
+
+// Get returns the category with the given name
+func (r *Registry) Get(name string) (*Category, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	cat, ok := r.categories[strings.ToLower(name)]
+	return cat, ok
+}
+
+// Resolve returns the named category, falling back to the default category for unknown names
+func (r *Registry) Resolve(name string) *Category {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if cat, ok := r.categories[strings.ToLower(name)]; ok {
+		return cat
+	}
+	return r.categories[DefaultName]
+}
+
+// Names returns category names in registration order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.order))
+	copy(names, r.order)
+	return names
+}
+
+// All returns categories in registration order
+func (r *Registry) All() []*Category {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	all := make([]*Category, 0, len(r.order))
+	for _, name := range r.order {
+		all = append(all, r.categories[name])
+	}
+	return all
+}
+
+// categoriesFile is the JSON layout of CATEGORIES_FILE
+type categoriesFile struct {
+	Categories []*Category `json:"categories"`
+}
+
+// loadFile reads category definitions from a JSON file
+func loadFile(path string) ([]*Category, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read categories file %s: %w", path, err)
+	}
+
+	var file categoriesFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse categories file %s: %w", path, err)
+	}
+
+	for i, cat := range file.Categories {
+		cat.Name = strings.ToLower(strings.TrimSpace(cat.Name))
+		if cat.Name == "" {
+			return nil, fmt.Errorf("category #%d in %s has no name", i+1, path)
+		}
+	}
+
+	return file.Categories, nil
+}
+
+// genericPrompt is used for categories that do not define their own prompt
+func genericPrompt(displayName string) string {
+	return `You are an expert ` + displayName + ` news curator for a daily Discord newsletter. Select the TOP ` + PlaceholderMaxItems + ` most significant ` + displayName + ` developments.
+
+## EVALUATION CRITERIA (in order of priority):
+
+1. **IMPACT** (40% weight): Stories with the broadest consequences for readers following ` + displayName + `
+2. **RECENCY** (25% weight): Prefer articles from last 24-48 hours
+3. **NOVELTY** (20% weight): New announcements, launches, and findings over follow-ups
+4. **CREDIBILITY** (15% weight): Official announcements and reputable publications
+
+## SELECTION RULES:
+✅ INCLUDE: Reputable publications, official announcements, significant developments
+❌ EXCLUDE: Duplicates, opinion pieces, unverified rumors, articles >7 days old
+
+## CLASSIFICATION:
+Tag each item with its sentiment (positive, negative, or neutral tone of the development for readers) and impact (low, medium, or high expected consequence).
+Give each item a relevance_score from 0 to 100 using the criteria above, where 90+ is a must-read and below 50 is filler. Score honestly: on a slow news day, low scores are expected.
+
+Return EXACTLY this JSON with ` + PlaceholderMaxItems + ` items ranked by importance:
+
+` + PlaceholderArticles + `
+
+{"news":[{"title":"Clear headline (max 100 chars)","summary":"Key facts and implications (max 250 chars)","url":"original_url","source":"publication","relevance":"Why significant (max 100 chars)","sentiment":"positive|negative|neutral","impact":"low|medium|high","relevance_score":85}]}`
+}
+
+// weeklyPrompt is the retrospective prompt used to pick the top stories of the week
+func weeklyPrompt(displayName string) string {
+	return `You are an expert ` + displayName + ` news editor writing the weekly retrospective for a Discord newsletter. The articles below are the stories that made this week's daily digests. Select the TOP ` + PlaceholderMaxItems + ` stories of the week.
+
+## EVALUATION CRITERIA (in order of priority):
+
+1. **LASTING SIGNIFICANCE** (40% weight): Stories that will still matter next month, not just the day they broke
+2. **NARRATIVE** (25% weight): Developments that defined the week or advanced an ongoing story
+3. **BREADTH OF IMPACT** (20% weight): Stories affecting many companies, markets, users, or regions
+4. **NOVELTY** (15% weight): Genuinely new launches, findings, or decisions
+
+## SELECTION RULES:
+✅ INCLUDE: The most consequential stories of the week, merging follow-ups of the same story into one item
+❌ EXCLUDE: Duplicates, minor updates, stories that were quickly superseded
+
+## WRITING STYLE:
+Write with a retrospective framing: summarize what happened over the week and why it mattered, rather than as breaking news.
+
+## CLASSIFICATION:
+Tag each item with its sentiment (positive, negative, or neutral tone of the development for readers) and impact (low, medium, or high expected consequence).
+Give each item a relevance_score from 0 to 100 using the criteria above, where 90+ is a must-read and below 50 is filler. Score honestly: on a slow news day, low scores are expected.
+
+Return EXACTLY this JSON with ` + PlaceholderMaxItems + ` items ranked by importance:
+
+` + PlaceholderArticles + `
+
+{"news":[{"title":"Clear headline (max 100 chars)","summary":"What happened this week and its implications (max 250 chars)","url":"original_url","source":"publication","relevance":"Why it was one of the week's defining stories (max 100 chars)","sentiment":"positive|negative|neutral","impact":"low|medium|high","relevance_score":85}]}`
+}
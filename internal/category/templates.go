@@ -0,0 +1,92 @@
+package category
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// templateExt is the file extension of digest templates in TEMPLATE_DIR
+const templateExt = ".tmpl"
+
+// DigestData is what digest templates can use: {{.Items}}, {{.Date}}, {{.Category}}, {{.TokenUsage}}, and {{.Weekly}}
+type DigestData struct {
+	Items      []models.NewsItem
+	Date       time.Time // Date of the digest
+	Category   *Category
+	TokenUsage *models.TokenUsage // Nil when usage is unknown
+	Weekly     bool
+}
+
+// templateFuncs are available to every digest template
+var templateFuncs = template.FuncMap{
+	// inc turns a zero-based index into a rank, e.g. {{inc $i}}
+	"inc": func(i int) int { return i + 1 },
+	// truncate shortens text to n characters, adding an ellipsis when cut
+	"truncate": func(n int, s string) string {
+		runes := []rune(s)
+		if len(runes) <= n {
+			return s
+		}
+		return strings.TrimSpace(string(runes[:max(n-1, 0)])) + "…"
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// loadTemplates parses every *.tmpl file in dir, keyed by file name without the extension
+func loadTemplates(dir string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template)
+	if dir == "" {
+		return templates, nil
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+templateExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates in %s: %w", dir, err)
+	}
+
+	for _, path := range paths {
+		text, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), templateExt)
+		tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+		templates[name] = tmpl
+	}
+
+	return templates, nil
+}
+
+// HasTemplate reports whether the category's digests are rendered with a template instead of the default embeds
+func (c *Category) HasTemplate() bool {
+	return c.tmpl != nil
+}
+
+// RenderDigest renders the category's digest template
+func (c *Category) RenderDigest(data DigestData) (string, error) {
+	if c.tmpl == nil {
+		return "", fmt.Errorf("category %q has no digest template", c.Name)
+	}
+
+	data.Category = c
+	var buf bytes.Buffer
+	if err := c.tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template for category %q: %w", c.Template, c.Name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
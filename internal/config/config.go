@@ -1,11 +1,11 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
-
-	"github.com/joho/godotenv"
+	"strings"
 )
 
 type Config struct {
@@ -13,52 +13,299 @@ type Config struct {
 	GeminiAPIKey         string
 	DiscordWebhook       string
 	DiscordWebhookGlobal string
+	DiscordWebhookLocal  string
+	DiscordWebhookCrypto string
+	DiscordThreadMode    string
+	DiscordThreadName    string
+	DiscordBotToken      string
+
+	// Gemini Safety Configuration
+	GeminiSafetyThreshold string
+	GeminiSafetySettings  string
 
 	// Server Configuration
-	Port    string
-	GinMode string
+	Port        string
+	GinMode     string
+	GRPCPort    string
+	AdminAPIKey string
+
+	// Signed Webhook Trigger Configuration
+	HookSecret           string
+	HookToleranceSeconds int
 
 	// News Configuration
-	MaxNewsItems int
+	MaxNewsItems      int
+	NewsSchedule      string
+	RunOnStart        bool
+	CatchUpMissedRuns bool
+	CategoriesFile    string
+	TemplateDir       string
+	DigestTemplate    string
+	CategoryOverrides map[string]CategoryOverride
+	DeepSummary       bool
+	MinRelevanceScore int
+	QualityScoring    bool
+	QualityMinScore   int
+	CurationCacheTTL  int // Minutes
+	InputTokenBudget  int
+
+	// Weekly Digest Configuration
+	WeeklyDigestSchedule string
+	WeeklyDigestMaxItems int
+	WeeklyDigestWebhook  string
+
+	// Storage and Polling Configuration
+	DataDir             string
+	JobRetentionDays    int
+	RawRetentionDays    int
+	DigestRetentionDays int
+	CleanupSchedule     string
+	PollIntervalMinutes int
+	TrendWindowDays     int
+
+	// WebSub Push Subscription Configuration
+	WebSubCallbackURL  string
+	WebSubLeaseSeconds int
+
+	// Digest Archive Configuration
+	ArchiveURL         string
+	ArchiveS3Endpoint  string
+	ArchiveS3Region    string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+
+	// Distributed Lock Configuration
+	LockRedisURL   string
+	LockTTLMinutes int
+
+	// Watchlist Configuration
+	WatchlistTerms   []string
+	WatchlistWebhook string
+
+	// Ops Alert Configuration
+	OpsWebhook       string
+	OpsAlertInterval int // Minutes
+
+	// Delivery Outbox Configuration
+	OutboxMaxAgeHours int
+
+	// Scraping Configuration
+	ScrapeConcurrency    int
+	ScrapeTimeoutSeconds int
+	ScraperUserAgent     string
+	RespectRobotsTxt     bool
+	ScraperProxyURL      string
 
 	// Timezone
 	Timezone string
 
+	// Secret Configuration
+	SecretRefreshMinutes int
+
 	// Logging
 	LogLevel string
+
+	secretRefs  map[string]string // Secret references by variable name, kept for RefreshSecrets
+	webhookRefs map[string]string // CATEGORY_<NAME>_WEBHOOK secret references by category name
 }
 
+// Load reads the configuration from the environment and .env. It can be called again
+// to reload, picking up edits to .env.
 func Load() (*Config, error) {
 	// Load .env file if it exists
-	if err := godotenv.Load(); err != nil {
-		// It's okay if .env doesn't exist, we'll use environment variables
-		if !os.IsNotExist(err) {
-			return nil, fmt.Errorf("error loading .env file: %w", err)
-		}
+	if err := loadDotEnv(); err != nil {
+		return nil, err
 	}
 
 	cfg := &Config{
-		GeminiAPIKey:         getEnv("GEMINI_API_KEY", ""),
-		DiscordWebhook:       getEnv("DISCORD_WEBHOOK", ""),
-		DiscordWebhookGlobal: getEnv("DISCORD_WEBHOOK_GLOBAL", getEnv("DISCORD_WEBHOOK", "")), // Fallback to main webhook
-		Port:                 getEnv("PORT", "6005"),
-		GinMode:              getEnv("GIN_MODE", "release"),
-		MaxNewsItems:         getEnvInt("MAX_NEWS_ITEMS", 5), // Default to 10 items as requested
-		Timezone:             getEnv("TZ", "Asia/Jakarta"),
-		LogLevel:             getEnv("LOG_LEVEL", "info"),
+		GeminiAPIKey:          getEnv("GEMINI_API_KEY", ""),
+		DiscordWebhook:        getEnv("DISCORD_WEBHOOK", ""),
+		DiscordWebhookGlobal:  getEnv("DISCORD_WEBHOOK_GLOBAL", getEnv("DISCORD_WEBHOOK", "")), // Fallback to main webhook
+		DiscordWebhookLocal:   getEnv("DISCORD_WEBHOOK_LOCAL", getEnv("DISCORD_WEBHOOK", "")),  // Fallback to main webhook
+		DiscordWebhookCrypto:  getEnv("DISCORD_WEBHOOK_CRYPTO", getEnv("DISCORD_WEBHOOK", "")), // Fallback to main webhook
+		DiscordThreadMode:     getEnv("DISCORD_THREAD_MODE", ""),                               // Empty posts digests into the channel
+		DiscordThreadName:     getEnv("DISCORD_THREAD_NAME", "{{category}} News — {{date}}"),
+		DiscordBotToken:       getEnv("DISCORD_BOT_TOKEN", ""),
+		GeminiSafetyThreshold: getEnv("GEMINI_SAFETY_THRESHOLD", ""), // Empty keeps the Gemini defaults
+		GeminiSafetySettings:  getEnv("GEMINI_SAFETY_SETTINGS", ""),
+		Port:                  getEnv("PORT", "6005"),
+		GinMode:               getEnv("GIN_MODE", "release"),
+		GRPCPort:              getEnv("GRPC_PORT", ""),     // Empty disables the gRPC API
+		AdminAPIKey:           getEnv("ADMIN_API_KEY", ""), // Empty disables the admin endpoints
+		HookSecret:            getEnv("HOOK_SECRET", ""),   // Empty disables the signed trigger endpoint
+		HookToleranceSeconds:  getEnvInt("HOOK_TOLERANCE_SECONDS", 300),
+		MaxNewsItems:          getEnvInt("MAX_NEWS_ITEMS", 5), // Default to 10 items as requested
+		CategoriesFile:        getEnv("CATEGORIES_FILE", ""),
+		TemplateDir:           getEnv("TEMPLATE_DIR", ""),    // Directory of *.tmpl digest templates
+		DigestTemplate:        getEnv("DIGEST_TEMPLATE", ""), // Empty uses the default embeds
+		NewsSchedule:          getEnv("NEWS_SCHEDULE", "0 8 * * *"),
+		InputTokenBudget:      getEnvInt("GEMINI_INPUT_TOKEN_BUDGET", 12000), // 0 uses the fixed per-category article cap
+		RunOnStart:            getEnvBool("RUN_ON_START", false),
+		CatchUpMissedRuns:     getEnvBool("CATCH_UP_MISSED_RUNS", false),
+		DeepSummary:           getEnvBool("DEEP_SUMMARY", false),
+		MinRelevanceScore:     getEnvInt("MIN_RELEVANCE_SCORE", 0),         // 0 keeps every selected item
+		QualityScoring:        getEnvBool("QUALITY_SCORING", true),         // Rank articles before curation
+		QualityMinScore:       getEnvInt("QUALITY_MIN_SCORE", 0),           // 0 sends every ranked article
+		CurationCacheTTL:      getEnvInt("CURATION_CACHE_TTL_MINUTES", 30), // 0 disables the curation cache
+		WeeklyDigestSchedule:  getEnv("WEEKLY_DIGEST_SCHEDULE", ""),        // Empty disables the weekly digest
+		WeeklyDigestMaxItems:  getEnvInt("WEEKLY_DIGEST_MAX_ITEMS", 10),
+		WeeklyDigestWebhook:   getEnv("WEEKLY_DIGEST_WEBHOOK", ""), // Empty uses each category's webhook
+		DataDir:               getEnv("DATA_DIR", "data"),
+		JobRetentionDays:      getEnvInt("JOB_HISTORY_RETENTION_DAYS", 90), // 0 keeps job history forever
+		RawRetentionDays:      getEnvInt("RAW_RETENTION_DAYS", 30),         // 0 keeps scraped articles forever
+		DigestRetentionDays:   getEnvInt("DIGEST_RETENTION_DAYS", 365),     // 0 keeps digests forever
+		CleanupSchedule:       getEnv("CLEANUP_SCHEDULE", "30 3 * * *"),    // Empty disables the cleanup job
+		PollIntervalMinutes:   getEnvInt("POLL_INTERVAL_MINUTES", 0),       // 0 disables polling mode
+		TrendWindowDays:       getEnvInt("TREND_WINDOW_DAYS", 7),           // 0 disables developing story annotations
+		WebSubCallbackURL:     getEnv("WEBSUB_CALLBACK_URL", ""),           // Empty disables WebSub subscriptions
+		WebSubLeaseSeconds:    getEnvInt("WEBSUB_LEASE_SECONDS", 864000),
+		ArchiveURL:            getEnv("ARCHIVE_URL", ""),         // Empty disables the digest archive
+		ArchiveS3Endpoint:     getEnv("ARCHIVE_S3_ENDPOINT", ""), // Empty uses AWS S3
+		ArchiveS3Region:       getEnv("ARCHIVE_S3_REGION", getEnv("AWS_REGION", "us-east-1")),
+		AWSAccessKeyID:        getEnv("AWS_ACCESS_KEY_ID", ""),
+		AWSSecretAccessKey:    getEnv("AWS_SECRET_ACCESS_KEY", ""),
+		AWSSessionToken:       getEnv("AWS_SESSION_TOKEN", ""),
+		LockRedisURL:          getEnv("LOCK_REDIS_URL", ""), // Empty disables the distributed scheduler lock
+		LockTTLMinutes:        getEnvInt("LOCK_TTL_MINUTES", 30),
+		WatchlistTerms:        getEnvList("WATCHLIST", nil),
+		WatchlistWebhook:      getEnv("WATCHLIST_WEBHOOK", ""),
+		OpsWebhook:            getEnv("OPS_WEBHOOK", ""),                   // Empty only logs errors
+		OpsAlertInterval:      getEnvInt("OPS_ALERT_INTERVAL_MINUTES", 60), // 0 sends every error
+		OutboxMaxAgeHours:     getEnvInt("OUTBOX_MAX_AGE_HOURS", 24),       // 0 disables delivery retries
+		ScrapeConcurrency:     getEnvInt("SCRAPE_CONCURRENCY", 4),
+		ScrapeTimeoutSeconds:  getEnvInt("SCRAPE_TIMEOUT_SECONDS", 30),
+		ScraperUserAgent:      getEnv("SCRAPER_USER_AGENT", "NewsScrappingBot/1.0 (+https://github.com/hengliuu/news-scrapping)"),
+		RespectRobotsTxt:      getEnvBool("RESPECT_ROBOTS_TXT", true),
+		ScraperProxyURL:       getEnv("SCRAPER_PROXY_URL", ""),
+		Timezone:              getEnv("TZ", "Asia/Jakarta"),
+		LogLevel:              getEnv("LOG_LEVEL", "info"),
+		SecretRefreshMinutes:  getEnvInt("SECRET_REFRESH_MINUTES", 0), // 0 resolves secret references only at load
 	}
 
-	// Validate required configuration
-	if cfg.GeminiAPIKey == "" {
-		return nil, fmt.Errorf("GEMINI_API_KEY is required")
+	// Resolve gcpsm:// and vault:// secret references
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	secrets := newSecretResolver(ctx)
+	if err := cfg.resolveSecrets(secrets); err != nil {
+		return nil, err
 	}
-	if cfg.DiscordWebhook == "" {
-		return nil, fmt.Errorf("DISCORD_WEBHOOK is required")
+
+	overrides, err := loadCategoryOverrides()
+	if err != nil {
+		return nil, err
+	}
+	cfg.webhookRefs = make(map[string]string)
+	for name, override := range overrides {
+		if isSecretRef(override.Webhook) {
+			cfg.webhookRefs[name] = override.Webhook
+		}
+		if override.Webhook, err = secrets.resolve(override.Webhook); err != nil {
+			return nil, fmt.Errorf("failed to resolve CATEGORY_%s_WEBHOOK: %w", strings.ToUpper(name), err)
+		}
+		overrides[name] = override
+	}
+	cfg.CategoryOverrides = overrides
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
 }
 
+// secretField is a configuration field that may hold a secret reference
+type secretField struct {
+	name  string
+	value *string
+}
+
+// secretFields returns the fields that commonly hold credentials
+func (c *Config) secretFields() []secretField {
+	return []secretField{
+		{"GEMINI_API_KEY", &c.GeminiAPIKey},
+		{"DISCORD_WEBHOOK", &c.DiscordWebhook},
+		{"DISCORD_WEBHOOK_GLOBAL", &c.DiscordWebhookGlobal},
+		{"DISCORD_WEBHOOK_LOCAL", &c.DiscordWebhookLocal},
+		{"DISCORD_WEBHOOK_CRYPTO", &c.DiscordWebhookCrypto},
+		{"DISCORD_BOT_TOKEN", &c.DiscordBotToken},
+		{"WEEKLY_DIGEST_WEBHOOK", &c.WeeklyDigestWebhook},
+		{"WATCHLIST_WEBHOOK", &c.WatchlistWebhook},
+		{"OPS_WEBHOOK", &c.OpsWebhook},
+		{"ADMIN_API_KEY", &c.AdminAPIKey},
+		{"HOOK_SECRET", &c.HookSecret},
+		{"LOCK_REDIS_URL", &c.LockRedisURL},
+		{"AWS_ACCESS_KEY_ID", &c.AWSAccessKeyID},
+		{"AWS_SECRET_ACCESS_KEY", &c.AWSSecretAccessKey},
+		{"AWS_SESSION_TOKEN", &c.AWSSessionToken},
+		{"SCRAPER_PROXY_URL", &c.ScraperProxyURL},
+	}
+}
+
+// resolveSecrets replaces secret references in the fields that commonly hold credentials
+func (c *Config) resolveSecrets(secrets *secretResolver) error {
+	c.secretRefs = make(map[string]string)
+	for _, field := range c.secretFields() {
+		if isSecretRef(*field.value) {
+			c.secretRefs[field.name] = *field.value
+		}
+		resolved, err := secrets.resolve(*field.value)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", field.name, err)
+		}
+		*field.value = resolved
+	}
+	return nil
+}
+
+// RefreshSecrets fetches the secret references the configuration was loaded with again and
+// returns a copy holding the current values, along with the names of the secrets that changed.
+// Nothing else is re-read; use Load to pick up other changes.
+func (c *Config) RefreshSecrets() (*Config, []string, error) {
+	refreshed := *c
+	refreshed.CategoryOverrides = make(map[string]CategoryOverride, len(c.CategoryOverrides))
+	for name, override := range c.CategoryOverrides {
+		refreshed.CategoryOverrides[name] = override
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	secrets := newSecretResolver(ctx)
+
+	var rotated []string
+	for _, field := range refreshed.secretFields() {
+		ref, ok := c.secretRefs[field.name]
+		if !ok {
+			continue
+		}
+		resolved, err := secrets.resolve(ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve %s: %w", field.name, err)
+		}
+		if resolved != *field.value {
+			*field.value = resolved
+			rotated = append(rotated, field.name)
+		}
+	}
+
+	for name, ref := range c.webhookRefs {
+		resolved, err := secrets.resolve(ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve CATEGORY_%s_WEBHOOK: %w", strings.ToUpper(name), err)
+		}
+		if override := refreshed.CategoryOverrides[name]; resolved != override.Webhook {
+			override.Webhook = resolved
+			refreshed.CategoryOverrides[name] = override
+			rotated = append(rotated, fmt.Sprintf("CATEGORY_%s_WEBHOOK", strings.ToUpper(name)))
+		}
+	}
+
+	if err := refreshed.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return &refreshed, rotated, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -74,3 +321,25 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvList(key string, defaultValue []string) []string {
+	if value := os.Getenv(key); value != "" {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return defaultValue
+}
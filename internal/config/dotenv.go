@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+// dotenvFile is the optional file of environment variables loaded by Load
+const dotenvFile = ".env"
+
+var (
+	dotenvMu sync.Mutex
+	// processEnv holds the variables set before .env was first loaded; they always win over .env
+	processEnv map[string]bool
+	// dotenvKeys holds the variables last applied from .env, so removed entries can be unset on reload
+	dotenvKeys map[string]bool
+)
+
+// loadDotEnv applies .env to the process environment without overriding real environment
+// variables. It can be called again to pick up edits, including removed entries.
+func loadDotEnv() error {
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+
+	if processEnv == nil {
+		processEnv = make(map[string]bool)
+		for _, env := range os.Environ() {
+			key, _, _ := strings.Cut(env, "=")
+			processEnv[key] = true
+		}
+	}
+
+	values, err := godotenv.Read(dotenvFile)
+	if err != nil {
+		// It's okay if .env doesn't exist, we'll use environment variables
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("error loading .env file: %w", err)
+		}
+		values = map[string]string{}
+	}
+
+	for key := range dotenvKeys {
+		if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+		}
+	}
+
+	keys := make(map[string]bool, len(values))
+	for key, value := range values {
+		if processEnv[key] {
+			continue
+		}
+		os.Setenv(key, value)
+		keys[key] = true
+	}
+	dotenvKeys = keys
+
+	return nil
+}
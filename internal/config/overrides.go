@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CategoryOverride holds per-category settings from CATEGORY_<NAME>_<FIELD> environment variables
+type CategoryOverride struct {
+	MaxItems   int
+	Schedule   string
+	Webhook    string
+	PromptFile string
+	Template   string
+}
+
+// categoryOverridePrefix is the prefix of per-category environment variables
+const categoryOverridePrefix = "CATEGORY_"
+
+// loadCategoryOverrides collects CATEGORY_<NAME>_MAX_ITEMS, _SCHEDULE, _WEBHOOK, _PROMPT_FILE, and _TEMPLATE
+// variables, keyed by lowercase category name (e.g. CATEGORY_GLOBAL_SCHEDULE -> "global")
+func loadCategoryOverrides() (map[string]CategoryOverride, error) {
+	overrides := make(map[string]CategoryOverride)
+
+	for _, env := range os.Environ() {
+		key, value, found := strings.Cut(env, "=")
+		if !found || value == "" || !strings.HasPrefix(key, categoryOverridePrefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(key, categoryOverridePrefix)
+
+		var name string
+		var apply func(*CategoryOverride) error
+		switch {
+		case strings.HasSuffix(rest, "_MAX_ITEMS"):
+			name = strings.TrimSuffix(rest, "_MAX_ITEMS")
+			apply = func(o *CategoryOverride) error {
+				maxItems, err := strconv.Atoi(value)
+				if err != nil || maxItems < 1 {
+					return fmt.Errorf("%s must be a positive integer, got %q", key, value)
+				}
+				o.MaxItems = maxItems
+				return nil
+			}
+		case strings.HasSuffix(rest, "_SCHEDULE"):
+			name = strings.TrimSuffix(rest, "_SCHEDULE")
+			apply = func(o *CategoryOverride) error {
+				o.Schedule = value
+				return nil
+			}
+		case strings.HasSuffix(rest, "_WEBHOOK"):
+			name = strings.TrimSuffix(rest, "_WEBHOOK")
+			apply = func(o *CategoryOverride) error {
+				o.Webhook = value
+				return nil
+			}
+		case strings.HasSuffix(rest, "_PROMPT_FILE"):
+			name = strings.TrimSuffix(rest, "_PROMPT_FILE")
+			apply = func(o *CategoryOverride) error {
+				o.PromptFile = value
+				return nil
+			}
+		case strings.HasSuffix(rest, "_TEMPLATE"):
+			name = strings.TrimSuffix(rest, "_TEMPLATE")
+			apply = func(o *CategoryOverride) error {
+				o.Template = value
+				return nil
+			}
+		default:
+			continue
+		}
+
+		if name == "" {
+			continue
+		}
+		name = strings.ToLower(name)
+
+		override := overrides[name]
+		if err := apply(&override); err != nil {
+			return nil, err
+		}
+		overrides[name] = override
+	}
+
+	return overrides, nil
+}
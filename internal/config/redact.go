@@ -0,0 +1,134 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedValue replaces secrets in the redacted configuration
+const redactedValue = "[redacted]"
+
+// Redacted returns the effective configuration keyed by environment variable name,
+// with API keys, credentials, and webhook tokens replaced so it is safe to share
+func (c *Config) Redacted() map[string]interface{} {
+	overrides := make(map[string]interface{}, len(c.CategoryOverrides))
+	for name, override := range c.CategoryOverrides {
+		overrides[name] = map[string]interface{}{
+			"max_items":   override.MaxItems,
+			"schedule":    override.Schedule,
+			"webhook":     RedactURL(override.Webhook),
+			"prompt_file": override.PromptFile,
+			"template":    override.Template,
+		}
+	}
+
+	return map[string]interface{}{
+		"GEMINI_API_KEY":             redactSecret(c.GeminiAPIKey),
+		"GEMINI_SAFETY_THRESHOLD":    c.GeminiSafetyThreshold,
+		"GEMINI_SAFETY_SETTINGS":     c.GeminiSafetySettings,
+		"GEMINI_INPUT_TOKEN_BUDGET":  c.InputTokenBudget,
+		"DISCORD_WEBHOOK":            RedactURL(c.DiscordWebhook),
+		"DISCORD_WEBHOOK_GLOBAL":     RedactURL(c.DiscordWebhookGlobal),
+		"DISCORD_WEBHOOK_LOCAL":      RedactURL(c.DiscordWebhookLocal),
+		"DISCORD_WEBHOOK_CRYPTO":     RedactURL(c.DiscordWebhookCrypto),
+		"DISCORD_THREAD_MODE":        c.DiscordThreadMode,
+		"DISCORD_THREAD_NAME":        c.DiscordThreadName,
+		"DISCORD_BOT_TOKEN":          redactSecret(c.DiscordBotToken),
+		"PORT":                       c.Port,
+		"GIN_MODE":                   c.GinMode,
+		"GRPC_PORT":                  c.GRPCPort,
+		"ADMIN_API_KEY":              redactSecret(c.AdminAPIKey),
+		"HOOK_SECRET":                redactSecret(c.HookSecret),
+		"HOOK_TOLERANCE_SECONDS":     c.HookToleranceSeconds,
+		"MAX_NEWS_ITEMS":             c.MaxNewsItems,
+		"NEWS_SCHEDULE":              c.NewsSchedule,
+		"RUN_ON_START":               c.RunOnStart,
+		"CATCH_UP_MISSED_RUNS":       c.CatchUpMissedRuns,
+		"CATEGORIES_FILE":            c.CategoriesFile,
+		"TEMPLATE_DIR":               c.TemplateDir,
+		"DIGEST_TEMPLATE":            c.DigestTemplate,
+		"CATEGORY_OVERRIDES":         overrides,
+		"DEEP_SUMMARY":               c.DeepSummary,
+		"MIN_RELEVANCE_SCORE":        c.MinRelevanceScore,
+		"QUALITY_SCORING":            c.QualityScoring,
+		"QUALITY_MIN_SCORE":          c.QualityMinScore,
+		"CURATION_CACHE_TTL_MINUTES": c.CurationCacheTTL,
+		"WEEKLY_DIGEST_SCHEDULE":     c.WeeklyDigestSchedule,
+		"WEEKLY_DIGEST_MAX_ITEMS":    c.WeeklyDigestMaxItems,
+		"WEEKLY_DIGEST_WEBHOOK":      RedactURL(c.WeeklyDigestWebhook),
+		"DATA_DIR":                   c.DataDir,
+		"JOB_HISTORY_RETENTION_DAYS": c.JobRetentionDays,
+		"RAW_RETENTION_DAYS":         c.RawRetentionDays,
+		"DIGEST_RETENTION_DAYS":      c.DigestRetentionDays,
+		"CLEANUP_SCHEDULE":           c.CleanupSchedule,
+		"POLL_INTERVAL_MINUTES":      c.PollIntervalMinutes,
+		"TREND_WINDOW_DAYS":          c.TrendWindowDays,
+		"WEBSUB_CALLBACK_URL":        c.WebSubCallbackURL,
+		"WEBSUB_LEASE_SECONDS":       c.WebSubLeaseSeconds,
+		"ARCHIVE_URL":                c.ArchiveURL,
+		"ARCHIVE_S3_ENDPOINT":        c.ArchiveS3Endpoint,
+		"ARCHIVE_S3_REGION":          c.ArchiveS3Region,
+		"AWS_ACCESS_KEY_ID":          redactSecret(c.AWSAccessKeyID),
+		"AWS_SECRET_ACCESS_KEY":      redactSecret(c.AWSSecretAccessKey),
+		"AWS_SESSION_TOKEN":          redactSecret(c.AWSSessionToken),
+		"LOCK_REDIS_URL":             RedactURL(c.LockRedisURL),
+		"LOCK_TTL_MINUTES":           c.LockTTLMinutes,
+		"WATCHLIST":                  c.WatchlistTerms,
+		"WATCHLIST_WEBHOOK":          RedactURL(c.WatchlistWebhook),
+		"OPS_WEBHOOK":                RedactURL(c.OpsWebhook),
+		"OPS_ALERT_INTERVAL_MINUTES": c.OpsAlertInterval,
+		"OUTBOX_MAX_AGE_HOURS":       c.OutboxMaxAgeHours,
+		"SCRAPE_CONCURRENCY":         c.ScrapeConcurrency,
+		"SCRAPE_TIMEOUT_SECONDS":     c.ScrapeTimeoutSeconds,
+		"SCRAPER_USER_AGENT":         c.ScraperUserAgent,
+		"RESPECT_ROBOTS_TXT":         c.RespectRobotsTxt,
+		"SCRAPER_PROXY_URL":          RedactURL(c.ScraperProxyURL),
+		"SECRET_REFRESH_MINUTES":     c.SecretRefreshMinutes,
+		"TZ":                         c.Timezone,
+		"LOG_LEVEL":                  c.LogLevel,
+	}
+}
+
+// redactSecret hides a secret while still showing whether it is set
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactedValue
+}
+
+// RedactURL keeps the scheme and host of a URL so misconfigured endpoints can be spotted,
+// hiding passwords, query strings, and webhook tokens. Discord webhooks keep their ID.
+func RedactURL(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return redactedValue
+	}
+
+	if parsed.User != nil {
+		parsed.User = url.User(redactedValue)
+	}
+	if parsed.RawQuery != "" {
+		parsed.RawQuery = redactedValue
+	}
+
+	path := strings.Trim(parsed.Path, "/")
+	switch {
+	case path == "":
+	case isDiscordHost(parsed.Hostname()) && strings.Contains(path, "webhooks/"):
+		// Keep /api/webhooks/<id> and hide the token
+		path = path[:strings.LastIndex(path, "/")]
+		parsed.Path = "/" + path + "/" + redactedValue
+	default:
+		parsed.Path = "/" + redactedValue
+	}
+	parsed.RawPath = ""
+
+	redacted := parsed.String()
+	// Keep the placeholder readable instead of percent-encoded
+	return strings.NewReplacer("%5Bredacted%5D", redactedValue, "%5bredacted%5d", redactedValue).Replace(redacted)
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// Secret reference schemes accepted in place of a plain value
+const (
+	gcpSecretPrefix   = "gcpsm://"
+	vaultSecretPrefix = "vault://"
+)
+
+// secretTimeout bounds resolving all secret references during one Load
+const secretTimeout = 30 * time.Second
+
+// isSecretRef reports whether a value is a secret manager reference
+func isSecretRef(value string) bool {
+	return strings.HasPrefix(value, gcpSecretPrefix) || strings.HasPrefix(value, vaultSecretPrefix)
+}
+
+// secretResolver resolves secret references, fetching each reference once per Load
+type secretResolver struct {
+	ctx        context.Context
+	cache      map[string]string
+	gcp        *secretmanager.Service // Created on first use
+	httpClient *http.Client
+}
+
+// newSecretResolver creates a resolver for one Load
+func newSecretResolver(ctx context.Context) *secretResolver {
+	return &secretResolver{
+		ctx:        ctx,
+		cache:      make(map[string]string),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// resolve returns value unchanged unless it is a gcpsm:// or vault:// reference,
+// in which case the referenced secret is fetched
+func (r *secretResolver) resolve(value string) (string, error) {
+	if !isSecretRef(value) {
+		return value, nil
+	}
+	if secret, ok := r.cache[value]; ok {
+		return secret, nil
+	}
+
+	var secret string
+	var err error
+	if strings.HasPrefix(value, gcpSecretPrefix) {
+		secret, err = r.resolveGCP(strings.TrimPrefix(value, gcpSecretPrefix))
+	} else {
+		secret, err = r.resolveVault(strings.TrimPrefix(value, vaultSecretPrefix))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	secret = strings.TrimSpace(secret)
+	r.cache[value] = secret
+	return secret, nil
+}
+
+// resolveGCP reads a GCP Secret Manager secret such as projects/x/secrets/gemini-key,
+// using the latest version unless one is given (projects/x/secrets/gemini-key/versions/3)
+func (r *secretResolver) resolveGCP(name string) (string, error) {
+	name = strings.Trim(name, "/")
+	parts := strings.Split(name, "/")
+	if len(parts) != 4 && len(parts) != 6 || parts[0] != "projects" || parts[2] != "secrets" {
+		return "", fmt.Errorf("invalid GCP secret reference %q, expected gcpsm://projects/<project>/secrets/<name>[/versions/<version>]", gcpSecretPrefix+name)
+	}
+	if len(parts) == 4 {
+		name += "/versions/latest"
+	}
+
+	if r.gcp == nil {
+		service, err := secretmanager.NewService(r.ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to create Secret Manager client: %w", err)
+		}
+		r.gcp = service
+	}
+
+	resp, err := r.gcp.Projects.Secrets.Versions.Access(name).Context(r.ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to access secret %s: %w", name, err)
+	}
+	if resp.Payload == nil {
+		return "", fmt.Errorf("secret %s has no payload", name)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// resolveVault reads a field of a Vault secret such as secret/data/news#gemini_key from
+// VAULT_ADDR with VAULT_TOKEN. Both KV version 1 and version 2 responses are supported.
+func (r *secretResolver) resolveVault(ref string) (string, error) {
+	path, field, found := strings.Cut(ref, "#")
+	path = strings.Trim(path, "/")
+	if !found || path == "" || field == "" {
+		return "", fmt.Errorf("invalid Vault secret reference %q, expected vault://<path>#<field>", vaultSecretPrefix+ref)
+	}
+
+	addr := getEnv("VAULT_ADDR", "")
+	token := getEnv("VAULT_TOKEN", "")
+	if addr == "" || token == "" {
+		return "", fmt.Errorf("VAULT_ADDR and VAULT_TOKEN are required to resolve %s", vaultSecretPrefix+ref)
+	}
+
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := getEnv("VAULT_NAMESPACE", ""); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Vault secret %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("Vault returned status %d for %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", fmt.Errorf("failed to decode Vault secret %s: %w", path, err)
+	}
+
+	data := secret.Data
+	// KV version 2 nests the fields under data.data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	value, ok := data[field].(string)
+	if !ok {
+		return "", fmt.Errorf("Vault secret %s has no string field %q", path, field)
+	}
+	return value, nil
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxNewsItemsLimit is the largest digest size accepted for MAX_NEWS_ITEMS and WEEKLY_DIGEST_MAX_ITEMS
+const maxNewsItemsLimit = 20
+
+// numericEnv lists integer variables, so values that are not numbers are reported instead of
+// silently falling back to the default
+var numericEnv = []string{
+	"MAX_NEWS_ITEMS", "GEMINI_INPUT_TOKEN_BUDGET", "MIN_RELEVANCE_SCORE", "QUALITY_MIN_SCORE", "CURATION_CACHE_TTL_MINUTES",
+	"WEEKLY_DIGEST_MAX_ITEMS", "JOB_HISTORY_RETENTION_DAYS", "RAW_RETENTION_DAYS", "DIGEST_RETENTION_DAYS",
+	"POLL_INTERVAL_MINUTES", "TREND_WINDOW_DAYS", "LOCK_TTL_MINUTES", "SCRAPE_CONCURRENCY",
+	"SCRAPE_TIMEOUT_SECONDS", "SECRET_REFRESH_MINUTES", "HOOK_TOLERANCE_SECONDS", "OPS_ALERT_INTERVAL_MINUTES", "OUTBOX_MAX_AGE_HOURS",
+	"WEBSUB_LEASE_SECONDS",
+}
+
+// booleanEnv lists boolean variables, checked like numericEnv
+var booleanEnv = []string{
+	"RUN_ON_START", "CATCH_UP_MISSED_RUNS", "DEEP_SUMMARY", "RESPECT_ROBOTS_TXT", "QUALITY_SCORING",
+}
+
+// Validate checks every configuration value and reports all problems at once,
+// each naming the variable to fix
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	for _, name := range numericEnv {
+		if value := os.Getenv(name); value != "" {
+			if _, err := strconv.Atoi(value); err != nil {
+				add("%s must be a whole number, got %q", name, value)
+			}
+		}
+	}
+	for _, name := range booleanEnv {
+		if value := os.Getenv(name); value != "" {
+			if _, err := strconv.ParseBool(value); err != nil {
+				add("%s must be true or false, got %q", name, value)
+			}
+		}
+	}
+
+	// Required values
+	if c.GeminiAPIKey == "" {
+		add("GEMINI_API_KEY is required")
+	}
+	if c.DiscordWebhook == "" {
+		add("DISCORD_WEBHOOK is required")
+	}
+	if len(c.WatchlistTerms) > 0 && c.WatchlistWebhook == "" {
+		add("WATCHLIST_WEBHOOK is required when WATCHLIST is set")
+	}
+
+	// Server
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		add("PORT must be a number between 1 and 65535, got %q", c.Port)
+	}
+	if c.GRPCPort != "" {
+		if port, err := strconv.Atoi(c.GRPCPort); err != nil || port < 1 || port > 65535 {
+			add("GRPC_PORT must be empty (disabled) or a number between 1 and 65535, got %q", c.GRPCPort)
+		} else if c.GRPCPort == c.Port {
+			add("GRPC_PORT must differ from PORT, both are %q", c.Port)
+		}
+	}
+	switch c.GinMode {
+	case "debug", "release", "test":
+	default:
+		add("GIN_MODE must be debug, release, or test, got %q", c.GinMode)
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		add("TZ must be an IANA timezone such as Asia/Jakarta or UTC, got %q", c.Timezone)
+	}
+
+	// Schedules
+	validateCron := func(name, spec string) {
+		if _, err := cron.ParseStandard(spec); err != nil {
+			add("%s must be a cron expression such as \"0 8 * * *\", got %q: %v", name, spec, err)
+		}
+	}
+	validateCron("NEWS_SCHEDULE", c.NewsSchedule)
+	if c.WeeklyDigestSchedule != "" {
+		validateCron("WEEKLY_DIGEST_SCHEDULE", c.WeeklyDigestSchedule)
+	}
+	if c.CleanupSchedule != "" {
+		validateCron("CLEANUP_SCHEDULE", c.CleanupSchedule)
+	}
+
+	// Webhooks
+	validateWebhook := func(name, value string) {
+		if value == "" {
+			return
+		}
+		if err := checkWebhookURL(value); err != nil {
+			add("%s %v", name, err)
+		}
+	}
+	validateWebhook("DISCORD_WEBHOOK", c.DiscordWebhook)
+	validateWebhook("DISCORD_WEBHOOK_GLOBAL", c.DiscordWebhookGlobal)
+	validateWebhook("DISCORD_WEBHOOK_LOCAL", c.DiscordWebhookLocal)
+	validateWebhook("DISCORD_WEBHOOK_CRYPTO", c.DiscordWebhookCrypto)
+	validateWebhook("WEEKLY_DIGEST_WEBHOOK", c.WeeklyDigestWebhook)
+	validateWebhook("WATCHLIST_WEBHOOK", c.WatchlistWebhook)
+	validateWebhook("OPS_WEBHOOK", c.OpsWebhook)
+
+	// Digest threads
+	switch c.DiscordThreadMode {
+	case "":
+	case "thread":
+		if c.DiscordBotToken == "" {
+			add("DISCORD_BOT_TOKEN is required when DISCORD_THREAD_MODE is thread")
+		}
+	case "forum":
+	default:
+		add("DISCORD_THREAD_MODE must be empty (disabled), thread, or forum, got %q", c.DiscordThreadMode)
+	}
+	if c.DiscordThreadMode != "" && strings.TrimSpace(c.DiscordThreadName) == "" {
+		add("DISCORD_THREAD_NAME must not be empty when DISCORD_THREAD_MODE is set")
+	}
+
+	// Digest templates
+	if c.DigestTemplate != "" && c.TemplateDir == "" {
+		add("TEMPLATE_DIR is required when DIGEST_TEMPLATE is set")
+	}
+
+	// Per-category overrides, in a stable order
+	names := make([]string, 0, len(c.CategoryOverrides))
+	for name := range c.CategoryOverrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		override := c.CategoryOverrides[name]
+		prefix := categoryOverridePrefix + strings.ToUpper(name)
+		if override.Schedule != "" {
+			validateCron(prefix+"_SCHEDULE", override.Schedule)
+		}
+		validateWebhook(prefix+"_WEBHOOK", override.Webhook)
+		// MaxItems is 0 when CATEGORY_<NAME>_MAX_ITEMS is not set
+		if override.MaxItems != 0 && (override.MaxItems < 1 || override.MaxItems > maxNewsItemsLimit) {
+			add("%s_MAX_ITEMS must be between 1 and %d, got %d", prefix, maxNewsItemsLimit, override.MaxItems)
+		}
+	}
+
+	// Bounds
+	if c.MaxNewsItems < 1 || c.MaxNewsItems > maxNewsItemsLimit {
+		add("MAX_NEWS_ITEMS must be between 1 and %d, got %d", maxNewsItemsLimit, c.MaxNewsItems)
+	}
+	if c.WeeklyDigestMaxItems < 1 || c.WeeklyDigestMaxItems > maxNewsItemsLimit {
+		add("WEEKLY_DIGEST_MAX_ITEMS must be between 1 and %d, got %d", maxNewsItemsLimit, c.WeeklyDigestMaxItems)
+	}
+	if c.MinRelevanceScore < 0 || c.MinRelevanceScore > 100 {
+		add("MIN_RELEVANCE_SCORE must be between 0 and 100, got %d", c.MinRelevanceScore)
+	}
+	if c.QualityMinScore < 0 || c.QualityMinScore > 100 {
+		add("QUALITY_MIN_SCORE must be between 0 and 100, got %d", c.QualityMinScore)
+	}
+	if c.PollIntervalMinutes < 0 {
+		add("POLL_INTERVAL_MINUTES must be 0 (disabled) or a positive number of minutes, got %d", c.PollIntervalMinutes)
+	}
+	if c.WebSubLeaseSeconds < 3600 {
+		add("WEBSUB_LEASE_SECONDS must be at least 3600, got %d", c.WebSubLeaseSeconds)
+	}
+	if c.HookToleranceSeconds < 1 {
+		add("HOOK_TOLERANCE_SECONDS must be a positive number of seconds, got %d", c.HookToleranceSeconds)
+	}
+	if c.OpsAlertInterval < 0 {
+		add("OPS_ALERT_INTERVAL_MINUTES must be 0 (no deduplication) or a positive number of minutes, got %d", c.OpsAlertInterval)
+	}
+	if c.OutboxMaxAgeHours < 0 {
+		add("OUTBOX_MAX_AGE_HOURS must be 0 (disabled) or a positive number of hours, got %d", c.OutboxMaxAgeHours)
+	}
+	if c.InputTokenBudget < 0 {
+		add("GEMINI_INPUT_TOKEN_BUDGET must be 0 (fixed article cap) or a positive number of tokens, got %d", c.InputTokenBudget)
+	}
+	if c.CurationCacheTTL < 0 {
+		add("CURATION_CACHE_TTL_MINUTES must be 0 (disabled) or a positive number of minutes, got %d", c.CurationCacheTTL)
+	}
+	if c.TrendWindowDays < 0 {
+		add("TREND_WINDOW_DAYS must be 0 (disabled) or a positive number of days, got %d", c.TrendWindowDays)
+	}
+	if c.JobRetentionDays < 0 {
+		add("JOB_HISTORY_RETENTION_DAYS must be 0 (keep forever) or a positive number of days, got %d", c.JobRetentionDays)
+	}
+	if c.RawRetentionDays < 0 {
+		add("RAW_RETENTION_DAYS must be 0 (keep forever) or a positive number of days, got %d", c.RawRetentionDays)
+	}
+	if c.DigestRetentionDays < 0 {
+		add("DIGEST_RETENTION_DAYS must be 0 (keep forever) or a positive number of days, got %d", c.DigestRetentionDays)
+	}
+	if c.LockTTLMinutes < 1 {
+		add("LOCK_TTL_MINUTES must be a positive number of minutes, got %d", c.LockTTLMinutes)
+	}
+	if c.ScrapeConcurrency < 1 {
+		add("SCRAPE_CONCURRENCY must be at least 1, got %d", c.ScrapeConcurrency)
+	}
+	if c.ScrapeTimeoutSeconds < 1 {
+		add("SCRAPE_TIMEOUT_SECONDS must be a positive number of seconds, got %d", c.ScrapeTimeoutSeconds)
+	}
+	if c.SecretRefreshMinutes < 0 {
+		add("SECRET_REFRESH_MINUTES must be 0 (disabled) or a positive number of minutes, got %d", c.SecretRefreshMinutes)
+	}
+
+	// Outbound connections
+	if c.ScraperProxyURL != "" {
+		proxyURL, err := url.Parse(c.ScraperProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			add("SCRAPER_PROXY_URL must be a URL like http://host:port")
+		} else {
+			switch proxyURL.Scheme {
+			case "http", "https", "socks5":
+			default:
+				add("SCRAPER_PROXY_URL scheme must be http, https, or socks5, got %q", proxyURL.Scheme)
+			}
+		}
+	}
+
+	if c.LockRedisURL != "" {
+		lockURL, err := url.Parse(c.LockRedisURL)
+		if err != nil || (lockURL.Scheme != "redis" && lockURL.Scheme != "rediss") {
+			add("LOCK_REDIS_URL must be a redis:// or rediss:// URL")
+		}
+	}
+
+	if c.WebSubCallbackURL != "" {
+		callbackURL, err := url.Parse(c.WebSubCallbackURL)
+		if err != nil || callbackURL.Host == "" || (callbackURL.Scheme != "https" && callbackURL.Scheme != "http") {
+			add("WEBSUB_CALLBACK_URL must be the public base URL of this service, like https://news.example.com, got %q", c.WebSubCallbackURL)
+		}
+	}
+
+	if c.ArchiveURL != "" {
+		archiveURL, err := url.Parse(c.ArchiveURL)
+		if err != nil || archiveURL.Host == "" {
+			add("ARCHIVE_URL must be a URL like s3://bucket/prefix or gs://bucket/prefix, got %q", c.ArchiveURL)
+		} else {
+			switch archiveURL.Scheme {
+			case "s3":
+				if c.AWSAccessKeyID == "" || c.AWSSecretAccessKey == "" {
+					add("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required for an s3:// ARCHIVE_URL " +
+						"(instance roles, ECS task roles, and ~/.aws profiles are not supported)")
+				}
+			case "gs":
+			default:
+				add("ARCHIVE_URL scheme must be s3 or gs, got %q", archiveURL.Scheme)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// checkWebhookURL checks that a webhook is an absolute http(s) URL and, for Discord hosts,
+// that it has the /api/webhooks/<id>/<token> shape
+func checkWebhookURL(value string) error {
+	webhookURL, err := url.Parse(value)
+	if err != nil || webhookURL.Host == "" || (webhookURL.Scheme != "https" && webhookURL.Scheme != "http") {
+		return fmt.Errorf("must be an http(s):// webhook URL")
+	}
+
+	if isDiscordHost(webhookURL.Hostname()) {
+		parts := strings.Split(strings.Trim(webhookURL.Path, "/"), "/")
+		if len(parts) < 4 || parts[0] != "api" || parts[len(parts)-3] != "webhooks" {
+			return fmt.Errorf("must look like https://discord.com/api/webhooks/<id>/<token>")
+		}
+	}
+	return nil
+}
+
+// isDiscordHost reports whether host serves Discord webhooks
+func isDiscordHost(host string) bool {
+	switch host {
+	case "discord.com", "discordapp.com", "ptb.discord.com", "canary.discord.com":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/category"
+)
+
+// Thread modes for daily digests
+const (
+	ThreadModeOff    = ""       // Post digests straight into the webhook's channel
+	ThreadModeThread = "thread" // Start a thread in the webhook's text channel (needs a bot token)
+	ThreadModeForum  = "forum"  // Create a post in the webhook's forum channel
+)
+
+// DefaultThreadName names digest threads, e.g. "AI Tech News — 2025-01-12"
+const DefaultThreadName = "{{category}} News — {{date}}"
+
+// maxThreadNameLength is Discord's limit on thread and forum post names
+const maxThreadNameLength = 100
+
+// threadAutoArchiveMinutes archives digest threads after a day without activity
+const threadAutoArchiveMinutes = 1440
+
+// ThreadOptions controls whether daily digests get their own thread or forum post
+type ThreadOptions struct {
+	Mode       string // ThreadModeOff, ThreadModeThread, or ThreadModeForum
+	NameFormat string // Supports {{category}} and {{date}}
+	BotToken   string // Required to start threads in text channels
+}
+
+// SetThreadOptions changes how daily digests are posted, e.g. after a configuration reload
+func (c *WebhookClient) SetThreadOptions(opts ThreadOptions) {
+	if opts.NameFormat == "" {
+		opts.NameFormat = DefaultThreadName
+	}
+	c.mu.Lock()
+	c.threads = opts
+	c.mu.Unlock()
+}
+
+// threadOptions returns the current thread options
+func (c *WebhookClient) threadOptions() ThreadOptions {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.threads
+}
+
+// threadName fills the name format for a category's digest, trimmed to Discord's limit
+func threadName(format string, cat *category.Category, date time.Time) string {
+	name := strings.NewReplacer(
+		"{{category}}", cat.DisplayName,
+		"{{date}}", date.Format("2006-01-02"),
+	).Replace(format)
+
+	if runes := []rune(name); len(runes) > maxThreadNameLength {
+		name = string(runes[:maxThreadNameLength])
+	}
+	return name
+}
+
+// createThread starts a public thread in the text channel a webhook posts to and returns its ID.
+// Webhooks cannot start threads without a message, so this uses the bot API.
+func (c *WebhookClient) createThread(webhookURL, name, botToken string) (string, error) {
+	if botToken == "" {
+		return "", fmt.Errorf("DISCORD_BOT_TOKEN is required to start threads")
+	}
+
+	// Look up the webhook's channel; the webhook token authorizes this request
+	var webhook struct {
+		ChannelID string `json:"channel_id"`
+	}
+	if err := c.discordRequest(http.MethodGet, webhookURL, "", nil, &webhook); err != nil {
+		return "", fmt.Errorf("failed to look up webhook channel: %w", err)
+	}
+	if webhook.ChannelID == "" {
+		return "", fmt.Errorf("webhook has no channel")
+	}
+
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	threadsURL := fmt.Sprintf("%s://%s/api/v10/channels/%s/threads", parsed.Scheme, parsed.Host, webhook.ChannelID)
+
+	body := map[string]interface{}{
+		"name":                  name,
+		"type":                  11, // Public thread
+		"auto_archive_duration": threadAutoArchiveMinutes,
+	}
+	var thread struct {
+		ID string `json:"id"`
+	}
+	if err := c.discordRequest(http.MethodPost, threadsURL, "Bot "+botToken, body, &thread); err != nil {
+		return "", fmt.Errorf("failed to create thread: %w", err)
+	}
+	if thread.ID == "" {
+		return "", fmt.Errorf("Discord returned no thread ID")
+	}
+	return thread.ID, nil
+}
+
+// discordRequest sends a JSON request to the Discord API and decodes the JSON response into out
+func (c *WebhookClient) discordRequest(method, requestURL, authorization string, body, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, requestURL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return redactError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Discord returned status %d", resp.StatusCode)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode Discord response: %w", err)
+		}
+	}
+	return nil
+}
+
+// withQuery returns the webhook URL with a query parameter set, e.g. thread_id or wait
+func withQuery(webhookURL, key, value string) string {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return webhookURL
+	}
+	query := parsed.Query()
+	query.Set(key, value)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
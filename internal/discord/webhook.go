@@ -3,11 +3,17 @@ package discord
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/config"
 	"github.com/hengky/news-scrapping/pkg/models"
 )
 
@@ -15,6 +21,9 @@ import (
 type WebhookClient struct {
 	webhookURL string
 	httpClient *http.Client
+
+	mu      sync.RWMutex
+	threads ThreadOptions
 }
 
 // New creates a new Discord webhook client
@@ -27,6 +36,12 @@ func New(webhookURL string) *WebhookClient {
 	}
 }
 
+// maxEmbedsPerMessage is Discord's limit on embeds in one webhook message
+const maxEmbedsPerMessage = 10
+
+// maxContentLength is Discord's limit on the text content of one message
+const maxContentLength = 2000
+
 // DiscordEmbed represents a Discord embed structure
 type DiscordEmbed struct {
 	Title       string       `json:"title"`
@@ -44,54 +59,129 @@ type EmbedFooter struct {
 
 // DiscordMessage represents a Discord webhook message
 type DiscordMessage struct {
-	Content string         `json:"content,omitempty"`
-	Embeds  []DiscordEmbed `json:"embeds,omitempty"`
+	Content    string         `json:"content,omitempty"`
+	Embeds     []DiscordEmbed `json:"embeds,omitempty"`
+	ThreadName string         `json:"thread_name,omitempty"` // Creates a post when sent to a forum channel
 }
 
-// SendNews sends curated AI news to Discord (backward compatibility)
-func (c *WebhookClient) SendNews(newsResponse *models.NewsResponse) error {
-	return c.SendNewsByType(newsResponse, "ai")
+// SendNewsForCategory sends curated news to the category webhook with category-specific formatting.
+// Categories without a webhook fall back to the client's default webhook. The date is the digest's
+// date shown in the header, which differs from today when a delivery is retried. Progress is
+// updated as messages are posted and lets a retry resume a partly delivered digest.
+func (c *WebhookClient) SendNewsForCategory(newsResponse *models.NewsResponse, cat *category.Category, date time.Time, progress *models.DeliveryProgress) error {
+	webhookURL := cat.Webhook
+	if webhookURL == "" {
+		webhookURL = c.webhookURL
+	}
+	return c.SendNewsForCategoryToWebhook(newsResponse, cat, webhookURL, date, progress)
 }
 
-// SendNewsByType sends curated news to Discord with type-specific formatting
-func (c *WebhookClient) SendNewsByType(newsResponse *models.NewsResponse, newsType string) error {
-	return c.SendNewsByTypeToWebhook(newsResponse, newsType, c.webhookURL)
+// SendNewsForCategoryToWebhook sends news to a specific webhook URL
+func (c *WebhookClient) SendNewsForCategoryToWebhook(newsResponse *models.NewsResponse, cat *category.Category, webhookURL string, date time.Time, progress *models.DeliveryProgress) error {
+	if len(newsResponse.News) == 0 {
+		return fmt.Errorf("no news items to send")
+	}
+
+	log.Printf("Sending %d %s news items to Discord webhook %s", len(newsResponse.News), cat.Name, config.RedactURL(webhookURL))
+
+	// Create category-specific header and color
+	header := fmt.Sprintf("%s - %s", cat.Header, date.Format("January 2, 2006"))
+
+	// Give each daily digest its own thread or forum post when enabled
+	opts := c.threadOptions()
+	thread := ""
+	if opts.Mode != ThreadModeOff {
+		thread = threadName(opts.NameFormat, cat, date)
+	}
+	return c.sendDigest(newsResponse, cat, header, date, false, webhookURL, thread, progress)
 }
 
-// SendNewsByTypeToWebhook sends news to a specific webhook URL
-func (c *WebhookClient) SendNewsByTypeToWebhook(newsResponse *models.NewsResponse, newsType string, webhookURL string) error {
+// SendWeeklyDigest sends the week's top stories for a category, ending on the date, to a specific webhook URL
+func (c *WebhookClient) SendWeeklyDigest(newsResponse *models.NewsResponse, cat *category.Category, webhookURL string, date time.Time, progress *models.DeliveryProgress) error {
 	if len(newsResponse.News) == 0 {
 		return fmt.Errorf("no news items to send")
 	}
+	if webhookURL == "" {
+		webhookURL = cat.Webhook
+	}
 
-	log.Printf("Sending %d %s news items to Discord webhook %s", len(newsResponse.News), newsType, webhookURL)
+	log.Printf("Sending %d weekly %s news items to Discord webhook %s", len(newsResponse.News), cat.Name, config.RedactURL(webhookURL))
 
-	// Create type-specific header and emoji
-	var header string
-	var embedColor int
-	if newsType == "global" {
-		header = fmt.Sprintf("🌍 **Daily Global Tech News** - %s", time.Now().Format("January 2, 2006"))
-		embedColor = 0x1E88E5 // Blue color for global news
+	weekStart := date.AddDate(0, 0, -6)
+	header := fmt.Sprintf("📅 **Weekly %s Digest** - %s to %s", cat.DisplayName,
+		weekStart.Format("January 2"), date.Format("January 2, 2006"))
+	return c.sendDigest(newsResponse, cat, header, date, true, webhookURL, "", progress)
+}
+
+// sendDigest sends a digest rendered with the category template, or as the default embeds.
+// With a thread name, the digest goes into a new thread or forum post of that name.
+func (c *WebhookClient) sendDigest(newsResponse *models.NewsResponse, cat *category.Category, header string, date time.Time, weekly bool, webhookURL, thread string, progress *models.DeliveryProgress) error {
+	if progress == nil {
+		progress = &models.DeliveryProgress{}
+	}
+
+	var messages []DiscordMessage
+	if cat.HasTemplate() {
+		body, err := cat.RenderDigest(category.DigestData{
+			Items:      newsResponse.News,
+			Date:       date,
+			TokenUsage: newsResponse.TokenUsage,
+			Weekly:     weekly,
+		})
+		if err != nil {
+			return err
+		}
+		if body == "" {
+			return fmt.Errorf("%s template rendered an empty digest", cat.Template)
+		}
+		messages = textMessages(body)
 	} else {
-		header = fmt.Sprintf("🤖 **Daily AI Tech News** - %s", time.Now().Format("January 2, 2006"))
-		embedColor = 0x00D4AA // Green color for AI news
+		messages = embedMessages(newsResponse, header, cat.Color)
 	}
 
+	// A retry posts into the thread opened by an earlier attempt rather than opening another
+	if thread != "" && progress.SentMessages == 0 && progress.ThreadID == "" {
+		threadID, postedFirst, err := c.openThread(messages[0], webhookURL, thread)
+		if err != nil {
+			return err
+		}
+		progress.ThreadID = threadID
+		if postedFirst {
+			progress.SentMessages = 1
+		}
+	}
+	if progress.ThreadID != "" {
+		webhookURL = withQuery(webhookURL, "thread_id", progress.ThreadID)
+	}
+
+	// Send to Discord using the specific webhook, skipping messages an earlier attempt posted
+	for _, msg := range messages[min(progress.SentMessages, len(messages)):] {
+		if err := c.sendMessageToWebhook(msg, webhookURL); err != nil {
+			return err
+		}
+		progress.SentMessages++
+	}
+	return nil
+}
+
+// embedMessages builds a header followed by one embed per news item and a footer embed.
+// Digests with more embeds than Discord allows in one message are split across messages.
+func embedMessages(newsResponse *models.NewsResponse, header string, embedColor int) []DiscordMessage {
 	// Create Discord message with embeds
 	message := DiscordMessage{
 		Content: header,
-		Embeds:  make([]DiscordEmbed, 0, len(newsResponse.News)),
+		Embeds:  make([]DiscordEmbed, 0, len(newsResponse.News)+1),
 	}
 
 	// Convert each news item to Discord embed
 	for i, item := range newsResponse.News {
 		embed := DiscordEmbed{
-			Title:       fmt.Sprintf("%d. %s", i+1, item.Title),
+			Title:       fmt.Sprintf("%d. %s%s", i+1, sentimentEmoji(item.Sentiment), item.Title),
 			Description: formatDescription(item),
 			URL:         item.URL,
-			Color:       embedColor,
+			Color:       itemColor(item, embedColor),
 			Footer: &EmbedFooter{
-				Text: fmt.Sprintf("Source: %s", item.Source),
+				Text: formatFooter(item),
 			},
 			Timestamp: time.Now().Format(time.RFC3339),
 		}
@@ -114,8 +204,103 @@ func (c *WebhookClient) SendNewsByTypeToWebhook(newsResponse *models.NewsRespons
 	}
 	message.Embeds = append(message.Embeds, footerEmbed)
 
-	// Send to Discord using the specific webhook
-	return c.sendMessageToWebhook(message, webhookURL)
+	// Split into messages Discord accepts, the header only on the first message
+	var messages []DiscordMessage
+	embeds := message.Embeds
+	for len(embeds) > 0 {
+		n := min(len(embeds), maxEmbedsPerMessage)
+		messages = append(messages, DiscordMessage{Content: message.Content, Embeds: embeds[:n]})
+		message.Content = ""
+		embeds = embeds[n:]
+	}
+	return messages
+}
+
+// textMessages splits a rendered digest into messages within Discord's content limit,
+// breaking between lines where possible
+func textMessages(body string) []DiscordMessage {
+	var messages []DiscordMessage
+	var current strings.Builder
+	flush := func() {
+		if current.Len() > 0 {
+			messages = append(messages, DiscordMessage{Content: current.String()})
+			current.Reset()
+		}
+	}
+
+	for _, line := range strings.Split(body, "\n") {
+		// Hard-wrap lines that do not fit in a message on their own
+		for len([]rune(line)) > maxContentLength {
+			flush()
+			runes := []rune(line)
+			messages = append(messages, DiscordMessage{Content: string(runes[:maxContentLength])})
+			line = string(runes[maxContentLength:])
+		}
+
+		if current.Len() > 0 && len([]rune(current.String()))+1+len([]rune(line)) > maxContentLength {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(line)
+	}
+	flush()
+	return messages
+}
+
+// openThread starts the named thread or forum post for a digest and returns its ID. A forum post
+// is created by posting the digest's first message, which is reported through postedFirst. Threads
+// in text channels that cannot be started fall back to the channel itself (an empty ID) so the
+// digest is still delivered.
+func (c *WebhookClient) openThread(first DiscordMessage, webhookURL, name string) (threadID string, postedFirst bool, err error) {
+	opts := c.threadOptions()
+	switch opts.Mode {
+	case ThreadModeForum:
+		// The first message creates the post; wait for it to learn the post's thread ID
+		first.ThreadName = name
+		var created struct {
+			ChannelID string `json:"channel_id"`
+		}
+		if err := c.discordRequest(http.MethodPost, withQuery(webhookURL, "wait", "true"), "", first, &created); err != nil {
+			return "", false, fmt.Errorf("failed to create Discord forum post %q: %w", name, err)
+		}
+		if created.ChannelID == "" {
+			return "", false, fmt.Errorf("Discord returned no thread ID for forum post %q", name)
+		}
+		log.Printf("Created Discord forum post %q", name)
+		return created.ChannelID, true, nil
+
+	case ThreadModeThread:
+		threadID, err := c.createThread(webhookURL, name, opts.BotToken)
+		if err != nil {
+			log.Printf("Warning: Failed to start Discord thread %q, posting to the channel instead: %v", name, err)
+			return "", false, nil
+		}
+		log.Printf("Started Discord thread %q", name)
+		return threadID, false, nil
+	}
+	return "", false, nil
+}
+
+// SendWatchlistAlert sends an instant alert for an article matching a watchlist term
+func (c *WebhookClient) SendWatchlistAlert(item models.NewsItem, term string) error {
+	message := DiscordMessage{
+		Content: fmt.Sprintf("🚨 **Watchlist Alert** - matched \"%s\"", term),
+		Embeds: []DiscordEmbed{
+			{
+				Title:       item.Title,
+				Description: formatDescription(item),
+				URL:         item.URL,
+				Color:       0xFF5252, // Red color for alerts
+				Footer: &EmbedFooter{
+					Text: fmt.Sprintf("Source: %s", item.Source),
+				},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+	return c.sendMessage(message)
 }
 
 // SendSimpleMessage sends a simple text message to Discord
@@ -152,6 +337,7 @@ func (c *WebhookClient) sendMessageToWebhook(message DiscordMessage, webhookURL
 	// Send request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		err = redactError(err)
 		log.Printf("Failed to send Discord webhook: %v", err)
 		return fmt.Errorf("failed to send Discord webhook: %w", err)
 	}
@@ -165,6 +351,15 @@ func (c *WebhookClient) sendMessageToWebhook(message DiscordMessage, webhookURL
 	return nil
 }
 
+// redactError hides the webhook token in the URL that HTTP client errors include
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = config.RedactURL(urlErr.URL)
+	}
+	return err
+}
+
 // formatDescription formats the news item description for Discord
 func formatDescription(item models.NewsItem) string {
 	description := item.Summary
@@ -174,6 +369,14 @@ func formatDescription(item models.NewsItem) string {
 		description += "\n\n**Why it matters:** " + item.Relevance
 	}
 
+	// Give readers continuity for stories covered on earlier days
+	if item.Story != nil {
+		description += fmt.Sprintf("\n\n🧵 **Developing story, day %d**", item.Story.Day)
+		for _, earlier := range item.Story.Earlier {
+			description += fmt.Sprintf("\n• [%s](%s) - %s, %s", earlier.Title, earlier.URL, earlier.Source, earlier.Date.Format("Jan 2"))
+		}
+	}
+
 	// Add link
 	description += fmt.Sprintf("\n\n🔗 [Read more](%s)", item.URL)
 
@@ -199,3 +402,46 @@ func (c *WebhookClient) TestWebhook() error {
 
 	return c.sendMessage(testMessage)
 }
+
+// sentimentEmoji returns a title prefix showing the item's tone at a glance
+func sentimentEmoji(sentiment string) string {
+	switch sentiment {
+	case models.SentimentPositive:
+		return "📈 "
+	case models.SentimentNegative:
+		return "📉 "
+	case models.SentimentNeutral:
+		return "➖ "
+	default:
+		return ""
+	}
+}
+
+// itemColor tints an embed by sentiment, keeping the category color for neutral or unclassified items
+func itemColor(item models.NewsItem, categoryColor int) int {
+	switch item.Sentiment {
+	case models.SentimentPositive:
+		return 0x2ECC71 // Green
+	case models.SentimentNegative:
+		return 0xE74C3C // Red
+	default:
+		return categoryColor
+	}
+}
+
+// formatFooter shows the source and, when available, the relevance score and impact level
+func formatFooter(item models.NewsItem) string {
+	footer := fmt.Sprintf("Source: %s", item.Source)
+	if item.RelevanceScore != nil {
+		footer += fmt.Sprintf(" | Relevance: %d/100", *item.RelevanceScore)
+	}
+	switch item.Impact {
+	case models.ImpactHigh:
+		footer += " | 🔥 High impact"
+	case models.ImpactMedium:
+		footer += " | ⚡ Medium impact"
+	case models.ImpactLow:
+		footer += " | 💤 Low impact"
+	}
+	return footer
+}
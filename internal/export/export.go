@@ -0,0 +1,150 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// Supported export formats
+const (
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+)
+
+// csvHeader is the header row of CSV exports
+var csvHeader = []string{
+	"date", "category", "kind", "title", "summary", "url", "source",
+	"sentiment", "impact", "relevance_score", "published_at",
+}
+
+// ValidFormat reports whether format is a supported export format
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatMarkdown, FormatCSV, FormatJSON:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the HTTP content type of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// Filename returns the download filename for a digest in an export format
+func Filename(digest models.Digest, format string) string {
+	ext := format
+	if format == FormatMarkdown {
+		ext = "md"
+	}
+	return fmt.Sprintf("%s-%s-%s.%s", digest.Category, digest.Kind, digest.Date, ext)
+}
+
+// Markdown renders a digest as a Markdown document with one section per news item
+func Markdown(digest models.Digest, displayName string) []byte {
+	var b strings.Builder
+
+	title := "Daily"
+	if digest.Kind == models.DigestWeekly {
+		title = "Weekly"
+	}
+	fmt.Fprintf(&b, "# %s %s News Digest (%s)\n\n", displayName, title, digest.Date)
+
+	for i, item := range digest.News {
+		fmt.Fprintf(&b, "## %d. %s\n\n", i+1, escapeMarkdown(item.Title))
+		if item.Summary != "" {
+			fmt.Fprintf(&b, "%s\n\n", item.Summary)
+		}
+
+		var meta []string
+		if item.Source != "" {
+			meta = append(meta, fmt.Sprintf("**Source:** %s", item.Source))
+		}
+		if item.Sentiment != "" {
+			meta = append(meta, fmt.Sprintf("**Sentiment:** %s", item.Sentiment))
+		}
+		if item.Impact != "" {
+			meta = append(meta, fmt.Sprintf("**Impact:** %s", item.Impact))
+		}
+		if item.RelevanceScore != nil {
+			meta = append(meta, fmt.Sprintf("**Relevance:** %d/100", *item.RelevanceScore))
+		}
+		if len(meta) > 0 {
+			fmt.Fprintf(&b, "%s\n\n", strings.Join(meta, " · "))
+		}
+
+		if item.URL != "" {
+			fmt.Fprintf(&b, "[Read more](%s)\n\n", item.URL)
+		}
+	}
+
+	if len(digest.News) == 0 {
+		b.WriteString("_No news items in this digest._\n")
+	}
+
+	return []byte(b.String())
+}
+
+// CSV renders a digest as CSV with a header row and one row per news item
+func CSV(digest models.Digest) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	if err := w.Write(csvHeader); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, item := range digest.News {
+		publishedAt := ""
+		if !item.PublishedAt.IsZero() {
+			publishedAt = item.PublishedAt.Format("2006-01-02T15:04:05Z07:00")
+		}
+		relevanceScore := ""
+		if item.RelevanceScore != nil {
+			relevanceScore = strconv.Itoa(*item.RelevanceScore)
+		}
+
+		row := []string{
+			digest.Date,
+			digest.Category,
+			digest.Kind,
+			item.Title,
+			item.Summary,
+			item.URL,
+			item.Source,
+			item.Sentiment,
+			item.Impact,
+			relevanceScore,
+			publishedAt,
+		}
+		if err := w.Write(row); err != nil {
+			return nil, fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// escapeMarkdown escapes characters that would break a Markdown heading
+func escapeMarkdown(text string) string {
+	replacer := strings.NewReplacer("[", "\\[", "]", "\\]", "*", "\\*", "_", "\\_", "`", "\\`")
+	return replacer.Replace(text)
+}
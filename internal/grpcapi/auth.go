@@ -0,0 +1,60 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorize checks the admin API key sent as x-api-key metadata or as a Bearer token in the
+// authorization metadata. The gRPC API is disabled when no key is configured.
+func authorize(ctx context.Context, apiKey string) error {
+	if apiKey == "" {
+		return status.Error(codes.PermissionDenied, "gRPC API is disabled, set ADMIN_API_KEY to enable it")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := first(md.Get("x-api-key"))
+	if key == "" {
+		key = strings.TrimPrefix(first(md.Get("authorization")), "Bearer ")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid or missing API key")
+	}
+	return nil
+}
+
+// first returns the first metadata value, or an empty string
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// unaryAuthInterceptor rejects unary calls without the admin API key. The key is read on every
+// call so a reloaded or rotated key takes effect immediately.
+func unaryAuthInterceptor(apiKey func() string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, apiKey()); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor rejects streaming calls without the admin API key
+func streamAuthInterceptor(apiKey func() string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(stream.Context(), apiKey()); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
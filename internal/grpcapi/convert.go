@@ -0,0 +1,84 @@
+package grpcapi
+
+import (
+	"time"
+
+	"github.com/hengky/news-scrapping/pkg/models"
+	"github.com/hengky/news-scrapping/pkg/newspb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toNewsResponse converts curated news to its protobuf form
+func toNewsResponse(resp *models.NewsResponse) *newspb.NewsResponse {
+	out := &newspb.NewsResponse{Cached: resp.Cached}
+	for _, item := range resp.News {
+		out.News = append(out.News, toNewsItem(item))
+	}
+	if resp.TokenUsage != nil {
+		out.TokenUsage = &newspb.TokenUsage{
+			InputTokens:  resp.TokenUsage.InputTokens,
+			OutputTokens: resp.TokenUsage.OutputTokens,
+			TotalTokens:  resp.TokenUsage.TotalTokens,
+		}
+	}
+	return out
+}
+
+// toNewsItem converts a news item to its protobuf form
+func toNewsItem(item models.NewsItem) *newspb.NewsItem {
+	out := &newspb.NewsItem{
+		Title:       item.Title,
+		Summary:     item.Summary,
+		Url:         item.URL,
+		Source:      item.Source,
+		Relevance:   item.Relevance,
+		Sentiment:   item.Sentiment,
+		Impact:      item.Impact,
+		PublishedAt: timestamp(item.PublishedAt),
+	}
+	if item.RelevanceScore != nil {
+		out.RelevanceScore = int32(*item.RelevanceScore)
+	}
+
+	if item.Story != nil {
+		out.Story = &newspb.StoryContext{
+			Day:       int32(item.Story.Day),
+			FirstSeen: timestamp(item.Story.FirstSeen),
+			Sources:   int32(item.Story.Sources),
+		}
+		for _, earlier := range item.Story.Earlier {
+			out.Story.Earlier = append(out.Story.Earlier, &newspb.EarlierCoverage{
+				Title:  earlier.Title,
+				Url:    earlier.URL,
+				Source: earlier.Source,
+				Date:   timestamp(earlier.Date),
+			})
+		}
+	}
+
+	return out
+}
+
+// toJobProgress converts a job progress event to its protobuf form
+func toJobProgress(event models.JobProgress) *newspb.JobProgress {
+	return &newspb.JobProgress{
+		JobId:        event.JobID,
+		Type:         event.Type,
+		Trigger:      event.Trigger,
+		Stage:        event.Stage,
+		Message:      event.Message,
+		ScrapedCount: int32(event.ScrapedCount),
+		NewsCount:    int32(event.NewsCount),
+		Status:       event.Status,
+		Error:        event.Error,
+		Time:         timestamp(event.Time),
+	}
+}
+
+// timestamp converts a time, leaving zero times unset
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
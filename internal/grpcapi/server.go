@@ -0,0 +1,171 @@
+package grpcapi
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/scheduler"
+	"github.com/hengky/news-scrapping/pkg/models"
+	"github.com/hengky/news-scrapping/pkg/newspb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server implements the NewsService gRPC API on top of the scheduler, mirroring the REST endpoints
+type Server struct {
+	newspb.UnimplementedNewsServiceServer
+	scheduler *scheduler.Scheduler
+}
+
+// NewServer creates a gRPC server with the NewsService registered. Every call requires ADMIN_API_KEY.
+func NewServer(sched *scheduler.Scheduler) *grpc.Server {
+	apiKey := func() string { return sched.Config().AdminAPIKey }
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(unaryAuthInterceptor(apiKey)),
+		grpc.StreamInterceptor(streamAuthInterceptor(apiKey)),
+	)
+	newspb.RegisterNewsServiceServer(srv, &Server{scheduler: sched})
+	return srv
+}
+
+// TriggerJob starts the daily job of a category, or the weekly digest, in the background
+func (s *Server) TriggerJob(_ context.Context, req *newspb.TriggerJobRequest) (*newspb.TriggerJobResponse, error) {
+	if req.GetWeekly() {
+		if s.scheduler.IsWeeklyDigestRunning() {
+			return nil, status.Error(codes.ResourceExhausted, "weekly digest job is already running")
+		}
+
+		go func() {
+			if err := s.scheduler.RunWeeklyDigest(models.TriggerGRPC); err != nil {
+				log.Printf("gRPC-triggered weekly digest failed: %v", err)
+			}
+		}()
+
+		return &newspb.TriggerJobResponse{Weekly: true, TriggeredAt: timestamppb.Now()}, nil
+	}
+
+	cat, err := s.category(req.GetType())
+	if err != nil {
+		return nil, err
+	}
+	if s.scheduler.IsTypeRunning(cat.Name) {
+		return nil, status.Errorf(codes.ResourceExhausted, "%s news job is already running", cat.DisplayName)
+	}
+
+	go func() {
+		if err := s.scheduler.RunJobByType(cat.Name, models.TriggerGRPC); err != nil {
+			log.Printf("gRPC-triggered %s job failed: %v", cat.Name, err)
+		}
+	}()
+
+	return &newspb.TriggerJobResponse{Type: cat.Name, TriggeredAt: timestamppb.Now()}, nil
+}
+
+// StreamJobProgress streams progress events until the client disconnects, or until a job
+// finishes when until_finished is set. A client too slow to keep up is disconnected with
+// ResourceExhausted rather than silently missing events.
+func (s *Server) StreamJobProgress(req *newspb.StreamJobProgressRequest, stream grpc.ServerStreamingServer[newspb.JobProgress]) error {
+	events, unsubscribe := s.scheduler.SubscribeProgress()
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "progress stream fell behind, subscribe again")
+			}
+			if req.GetType() != "" && event.Type != req.GetType() {
+				continue
+			}
+			if err := stream.Send(toJobProgress(event)); err != nil {
+				return err
+			}
+			if req.GetUntilFinished() && event.Stage == models.StageFinished {
+				return nil
+			}
+		}
+	}
+}
+
+// GetLatestDigest returns the most recent stored digest of a category, optionally for a given date
+func (s *Server) GetLatestDigest(_ context.Context, req *newspb.GetLatestDigestRequest) (*newspb.GetLatestDigestResponse, error) {
+	cat, err := s.category(req.GetType())
+	if err != nil {
+		return nil, err
+	}
+
+	kind := req.GetKind()
+	if kind == "" {
+		kind = models.DigestDaily
+	}
+	if kind != models.DigestDaily && kind != models.DigestWeekly {
+		return nil, status.Error(codes.InvalidArgument, "kind must be daily or weekly")
+	}
+
+	if req.GetDate() != "" {
+		if _, err := time.Parse("2006-01-02", req.GetDate()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "date must be formatted as YYYY-MM-DD")
+		}
+	}
+
+	digest, ok := s.scheduler.LatestDigest(cat.Name, kind, req.GetDate())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no %s %s digest stored", kind, cat.Name)
+	}
+
+	return &newspb.GetLatestDigestResponse{
+		Id:        digest.ID,
+		Category:  digest.Category,
+		Kind:      digest.Kind,
+		Date:      digest.Date,
+		CreatedAt: timestamppb.New(digest.CreatedAt),
+		Digest: toNewsResponse(&models.NewsResponse{
+			News:       digest.News,
+			TokenUsage: digest.TokenUsage,
+		}),
+	}, nil
+}
+
+// ListSources lists the news sources of every category, or of the requested one
+func (s *Server) ListSources(_ context.Context, req *newspb.ListSourcesRequest) (*newspb.ListSourcesResponse, error) {
+	categories := s.scheduler.Categories().All()
+	if req.GetType() != "" {
+		cat, err := s.category(req.GetType())
+		if err != nil {
+			return nil, err
+		}
+		categories = []*category.Category{cat}
+	}
+
+	resp := &newspb.ListSourcesResponse{}
+	for _, cat := range categories {
+		for _, source := range cat.Sources {
+			resp.Sources = append(resp.Sources, &newspb.Source{
+				Category: cat.Name,
+				Name:     source.Name,
+				Url:      source.URL,
+				Type:     source.Type,
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+// category resolves a requested category, defaulting to the default category when empty
+func (s *Server) category(name string) (*category.Category, error) {
+	if name == "" {
+		name = category.DefaultName
+	}
+	cat, ok := s.scheduler.Categories().Get(name)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown news type %q, available: %v", name, s.scheduler.Categories().Names())
+	}
+	return cat, nil
+}
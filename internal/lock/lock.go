@@ -0,0 +1,66 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyPrefix namespaces lock keys in a shared Redis
+const keyPrefix = "news-scrapping:lock:"
+
+// Locker claims scheduled job slots so that only one replica runs each scheduled job
+type Locker interface {
+	// Claim reports whether this replica won the named slot. Claims are never released;
+	// they expire after the TTL so a later slot can be claimed again.
+	Claim(ctx context.Context, slot string) (bool, error)
+	Close() error
+}
+
+// RedisLocker claims slots with SET NX in Redis
+type RedisLocker struct {
+	client *redis.Client
+	owner  string
+	ttl    time.Duration
+}
+
+// NewRedis creates a Redis-backed locker from a redis:// or rediss:// URL
+func NewRedis(redisURL string, ttl time.Duration) (*RedisLocker, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lock Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to lock Redis: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	return &RedisLocker{
+		client: client,
+		owner:  fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		ttl:    ttl,
+	}, nil
+}
+
+// Claim sets the slot key if it does not exist yet
+func (l *RedisLocker) Claim(ctx context.Context, slot string) (bool, error) {
+	acquired, err := l.client.SetNX(ctx, keyPrefix+slot, l.owner, l.ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim %s: %w", slot, err)
+	}
+	return acquired, nil
+}
+
+// Close closes the Redis connection
+func (l *RedisLocker) Close() error {
+	return l.client.Close()
+}
@@ -0,0 +1,143 @@
+package ops
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/discord"
+)
+
+// digitsPattern matches the parts of error messages that vary between otherwise identical
+// failures, such as status codes, ports, durations, and IDs
+var digitsPattern = regexp.MustCompile(`[0-9]+`)
+
+// errorState tracks alerts sent for one error signature
+type errorState struct {
+	lastSent   time.Time
+	suppressed int // Repeats since the last alert
+}
+
+// Notifier sends error notifications to the ops webhook, sending at most one alert per
+// error signature per interval and counting the repeats in between
+type Notifier struct {
+	mu       sync.Mutex
+	discord  *discord.WebhookClient // nil when no ops webhook is set
+	interval time.Duration
+	errors   map[string]*errorState
+}
+
+// New creates a notifier posting to the webhook. An interval of 0 sends every error, and an
+// empty webhook sends nothing, leaving errors in the log only.
+func New(webhookURL string, interval time.Duration) *Notifier {
+	return &Notifier{
+		discord:  newClient(webhookURL),
+		interval: interval,
+		errors:   make(map[string]*errorState),
+	}
+}
+
+// SetWebhook changes the webhook and interval, e.g. after a configuration reload.
+// Suppression state is kept so a reload does not resend recent errors.
+func (n *Notifier) SetWebhook(webhookURL string, interval time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.discord = newClient(webhookURL)
+	n.interval = interval
+}
+
+// newClient returns a client for the ops webhook, or nil when none is set
+func newClient(webhookURL string) *discord.WebhookClient {
+	if webhookURL == "" {
+		return nil
+	}
+	return discord.New(webhookURL)
+}
+
+// Enabled reports whether an ops webhook is set
+func (n *Notifier) Enabled() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.discord != nil
+}
+
+// Report sends an error notification for a failed operation such as "scheduled ai job",
+// unless the same error was already reported within the interval
+func (n *Notifier) Report(operation string, err error) {
+	now := time.Now()
+	signature := Signature(operation, err)
+
+	n.mu.Lock()
+	if n.discord == nil {
+		n.mu.Unlock()
+		return
+	}
+	state, seen := n.errors[signature]
+	if seen && n.interval > 0 && now.Sub(state.lastSent) < n.interval {
+		state.suppressed++
+		n.mu.Unlock()
+		log.Printf("Suppressed repeated error notification for %s (%d since last alert)", operation, state.suppressed)
+		return
+	}
+	if !seen {
+		state = &errorState{}
+		n.errors[signature] = state
+	}
+	previous := *state
+	repeats := state.suppressed
+	state.lastSent = now
+	state.suppressed = 0
+	client := n.discord
+	n.pruneLocked(now)
+	n.mu.Unlock()
+
+	message := fmt.Sprintf("❌ **News Bot Error**\n\n%s failed at %s\n\nError: %s",
+		capitalize(operation), now.Format("2006-01-02 15:04:05 MST"), err.Error())
+	if repeats > 0 {
+		message += fmt.Sprintf("\n\n🔁 Repeated %d more times since %s", repeats, previous.lastSent.Format("2006-01-02 15:04:05 MST"))
+	}
+	message += fmt.Sprintf("\n-# Signature %s", signature)
+
+	if sendErr := client.SendSimpleMessage(message); sendErr != nil {
+		log.Printf("Failed to send error notification to ops webhook: %v", sendErr)
+
+		// Allow the next occurrence to try again
+		n.mu.Lock()
+		if current, ok := n.errors[signature]; ok && current == state {
+			state.lastSent = previous.lastSent
+			state.suppressed += repeats
+		}
+		n.mu.Unlock()
+	}
+}
+
+// pruneLocked forgets signatures that have not been alerted for several intervals
+func (n *Notifier) pruneLocked(now time.Time) {
+	ttl := max(n.interval*4, 24*time.Hour)
+	for signature, state := range n.errors {
+		if now.Sub(state.lastSent) > ttl {
+			delete(n.errors, signature)
+		}
+	}
+}
+
+// Signature identifies repeats of the same error: the operation and the error message with
+// numbers masked, hashed to a short hex string
+func Signature(operation string, err error) string {
+	normalized := strings.ToLower(operation) + "\n" + digitsPattern.ReplaceAllString(strings.ToLower(err.Error()), "#")
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:6])
+}
+
+// capitalize upper-cases the first letter of an operation name for the start of a sentence
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/config"
+)
+
+// cleanupEnabled reports whether the cleanup job is scheduled and has something to prune
+func cleanupEnabled(cfg *config.Config) bool {
+	if cfg.CleanupSchedule == "" {
+		return false
+	}
+	return cfg.RawRetentionDays > 0 || cfg.DigestRetentionDays > 0 || cfg.JobRetentionDays > 0
+}
+
+// runCleanupJob is the scheduled job function enforcing the retention policy
+func (s *Scheduler) runCleanupJob(_ string, slot time.Time, claimed func()) {
+	s.runScheduledJob("cleanup", slot, claimed, s.executeCleanup)
+}
+
+// executeCleanup removes scraped articles, digests, job records, and failed deliveries older than their retention periods
+func (s *Scheduler) executeCleanup() error {
+	now := time.Now()
+	cfg := s.cfg()
+	var failures []string
+
+	prune := func(what string, days int, prune func(time.Time) (int, error)) {
+		if days <= 0 {
+			return
+		}
+		removed, err := prune(now.AddDate(0, 0, -days))
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("failed to prune %s: %v", what, err))
+			return
+		}
+		if removed > 0 {
+			log.Printf("Pruned %d %s older than %d days", removed, what, days)
+		}
+	}
+
+	prune("articles", cfg.RawRetentionDays, s.store.PruneArticles)
+	prune("digests", cfg.DigestRetentionDays, s.store.PruneDigests)
+	prune("job records", cfg.JobRetentionDays, s.store.PruneJobs)
+	prune("failed deliveries", cfg.JobRetentionDays, s.store.PruneOutbox)
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
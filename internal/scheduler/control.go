@@ -0,0 +1,329 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/config"
+	"github.com/hengky/news-scrapping/pkg/models"
+	"github.com/robfig/cron/v3"
+)
+
+// Names of the scheduled jobs that are not category jobs
+const (
+	jobDaily   = "daily"
+	jobWeekly  = "weekly"
+	jobCleanup = "cleanup"
+)
+
+// jobRunner runs a scheduled job; slot is the scheduled time being run. claimed, when set, is
+// called once the run is going ahead: the scheduler is not paused and the slot was claimed.
+type jobRunner func(trigger string, slot time.Time, claimed func())
+
+// jobSpec is a scheduled job to register: its name, configured cron expression, and runner
+type jobSpec struct {
+	name   string
+	spec   string
+	runner jobRunner
+}
+
+// jobSpecs returns the scheduled jobs for a configuration and set of categories
+func (s *Scheduler) jobSpecs(cfg *config.Config, categories *category.Registry) []jobSpec {
+	// The daily job (08:00 WIB by default)
+	jobs := []jobSpec{{name: jobDaily, spec: cfg.NewsSchedule, runner: s.runNewsJob}}
+
+	// Categories with their own schedule run independently of the daily job
+	for _, cat := range categories.All() {
+		if cat.Schedule == "" {
+			continue
+		}
+		name := cat.Name
+		jobs = append(jobs, jobSpec{name: name, spec: cat.Schedule, runner: func(trigger string, slot time.Time, claimed func()) {
+			s.runCategoryJob(name, trigger, slot, claimed)
+		}})
+	}
+
+	// Optionally send a weekly retrospective of each category's top stories
+	if cfg.WeeklyDigestSchedule != "" {
+		jobs = append(jobs, jobSpec{name: jobWeekly, spec: cfg.WeeklyDigestSchedule, runner: s.runWeeklyDigestJob})
+	}
+
+	// Prune old articles, digests, and job history so the data directory stays bounded
+	if cleanupEnabled(cfg) {
+		jobs = append(jobs, jobSpec{name: jobCleanup, spec: cfg.CleanupSchedule, runner: s.runCleanupJob})
+	}
+
+	return jobs
+}
+
+// addScheduledJob adds a cron job, preferring a cron expression persisted through the admin API
+func (s *Scheduler) addScheduledJob(name, defaultSpec string, runner jobRunner, state models.SchedulerState) {
+	spec := defaultSpec
+	if override, ok := state.Schedules[name]; ok {
+		if _, err := cron.ParseStandard(override); err != nil {
+			log.Printf("Warning: Ignoring invalid persisted schedule %q for %s job: %v", override, name, err)
+		} else {
+			spec = override
+		}
+	}
+
+	run := func() {
+		now := time.Now()
+		s.recordScheduledRun(name, now)
+		runner(models.TriggerScheduled, now, nil)
+	}
+
+	id, err := s.cron.AddFunc(spec, run)
+	if err != nil {
+		log.Fatalf("Failed to schedule %s job with %q: %v", name, spec, err)
+	}
+
+	s.mu.Lock()
+	s.entries[name] = id
+	s.specs[name] = spec
+	s.defaults[name] = defaultSpec
+	s.jobFuncs[name] = run
+	s.runners[name] = runner
+	s.mu.Unlock()
+
+	log.Printf("%s job scheduled with %q", name, spec)
+}
+
+// Pause stops scheduled jobs and polling from running until Resume is called.
+// Manual triggers still run. The paused state survives restarts.
+func (s *Scheduler) Pause() error {
+	s.mu.Lock()
+	s.paused = true
+	s.mu.Unlock()
+
+	now := time.Now()
+	err := s.store.UpdateSchedulerState(func(state *models.SchedulerState) {
+		state.Paused = true
+		state.PausedAt = &now
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist paused state: %w", err)
+	}
+
+	log.Println("Scheduler paused")
+	return nil
+}
+
+// Resume lets scheduled jobs run again after Pause
+func (s *Scheduler) Resume() error {
+	s.mu.Lock()
+	s.paused = false
+	s.mu.Unlock()
+
+	err := s.store.UpdateSchedulerState(func(state *models.SchedulerState) {
+		state.Paused = false
+		state.PausedAt = nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist resumed state: %w", err)
+	}
+
+	log.Println("Scheduler resumed")
+	return nil
+}
+
+// IsPaused returns whether scheduled runs are paused
+func (s *Scheduler) IsPaused() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.paused
+}
+
+// Reschedule changes a scheduled job's cron expression at runtime and persists it.
+// An empty spec restores the configured schedule.
+func (s *Scheduler) Reschedule(name, spec string) (*models.ScheduledJob, error) {
+	s.mu.Lock()
+	run, ok := s.jobFuncs[name]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("unknown scheduled job %q", name)
+	}
+	if spec == "" {
+		spec = s.defaults[name]
+	}
+	if _, err := cron.ParseStandard(spec); err != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("invalid cron expression %q: %w", spec, err)
+	}
+
+	id, err := s.cron.AddFunc(spec, run)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("failed to schedule %s job with %q: %w", name, spec, err)
+	}
+	s.cron.Remove(s.entries[name])
+	s.entries[name] = id
+	s.specs[name] = spec
+	isDefault := spec == s.defaults[name]
+	s.mu.Unlock()
+
+	err = s.store.UpdateSchedulerState(func(state *models.SchedulerState) {
+		if isDefault {
+			delete(state.Schedules, name)
+		} else {
+			state.Schedules[name] = spec
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to persist schedule: %w", err)
+	}
+
+	log.Printf("%s job rescheduled with %q", name, spec)
+	s.updateNextRunTime()
+
+	job := s.scheduledJob(name)
+	return &job, nil
+}
+
+// ScheduledJobs returns the cron jobs that can be rescheduled, with their next run times
+func (s *Scheduler) ScheduledJobs() []models.ScheduledJob {
+	var names []string
+	s.mu.RLock()
+	for name := range s.jobFuncs {
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+
+	// Keep a stable order: daily first, then categories in registry order, then weekly and cleanup
+	ordered := []string{jobDaily}
+	ordered = append(ordered, s.categories.Names()...)
+	ordered = append(ordered, jobWeekly, jobCleanup)
+
+	jobs := make([]models.ScheduledJob, 0, len(names))
+	for _, name := range ordered {
+		for _, candidate := range names {
+			if candidate == name {
+				jobs = append(jobs, s.scheduledJob(name))
+				break
+			}
+		}
+	}
+	return jobs
+}
+
+// scheduledJob describes a single scheduled job
+func (s *Scheduler) scheduledJob(name string) models.ScheduledJob {
+	s.mu.RLock()
+	job := models.ScheduledJob{
+		Name:            name,
+		Schedule:        s.specs[name],
+		DefaultSchedule: s.defaults[name],
+	}
+	id := s.entries[name]
+	s.mu.RUnlock()
+
+	if next := s.cron.Entry(id).Next; !next.IsZero() {
+		job.NextRun = next.Format("2006-01-02 15:04:05 MST")
+	}
+	return job
+}
+
+// recordScheduledRun persists when a scheduled job last fired so missed runs can be detected
+func (s *Scheduler) recordScheduledRun(name string, at time.Time) {
+	err := s.store.UpdateSchedulerState(func(state *models.SchedulerState) {
+		state.LastRuns[name] = at
+	})
+	if err != nil {
+		log.Printf("Failed to persist last run of %s job: %v", name, err)
+	}
+}
+
+// runStartupJobs optionally runs the daily job on startup and catches up on scheduled runs
+// missed while the service was down. Jobs run one after another so they do not skip each other.
+func (s *Scheduler) runStartupJobs(state models.SchedulerState) {
+	now := time.Now()
+	cfg := s.cfg()
+
+	if cfg.RunOnStart {
+		log.Println("Running news job on startup")
+		s.runNewsJob(models.TriggerStartup, s.startupSlot(now), nil)
+	}
+
+	if cfg.CatchUpMissedRuns {
+		for _, job := range s.ScheduledJobs() {
+			if job.Name == jobDaily && cfg.RunOnStart {
+				continue // Already ran on startup
+			}
+
+			missed, ok := s.missedRun(job, state.LastRuns, now)
+			if !ok {
+				continue
+			}
+
+			log.Printf("Missed %s job scheduled at %s while down, running catch-up", job.Name,
+				missed.In(s.location).Format("2006-01-02 15:04 MST"))
+
+			// Record the catch-up only once it runs, so a paused or claimed run is not marked as done
+			name := job.Name
+			s.mu.RLock()
+			runner := s.runners[name]
+			s.mu.RUnlock()
+			runner(models.TriggerCatchUp, missed, func() {
+				s.recordScheduledRun(name, missed)
+			})
+		}
+	}
+
+	// Start tracking jobs that have never fired so later downtime can be detected
+	jobs := s.ScheduledJobs()
+	err := s.store.UpdateSchedulerState(func(state *models.SchedulerState) {
+		for _, job := range jobs {
+			if _, ok := state.LastRuns[job.Name]; !ok {
+				state.LastRuns[job.Name] = now
+			}
+		}
+	})
+	if err != nil {
+		log.Printf("Failed to persist scheduler state: %v", err)
+	}
+}
+
+// startupSlotLookback bounds the search for the last scheduled daily run; daily and weekly
+// schedules have always fired within it
+const startupSlotLookback = 8 * 24 * time.Hour
+
+// startupSlot is the slot claimed by the startup run: the most recent scheduled time of the daily
+// job, so replicas starting minutes apart claim the same slot and only one of them runs it.
+// Without an earlier scheduled time it falls back to now.
+func (s *Scheduler) startupSlot(now time.Time) time.Time {
+	s.mu.RLock()
+	spec := s.specs[jobDaily]
+	s.mu.RUnlock()
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return now
+	}
+
+	slot := now
+	for next := schedule.Next(now.Add(-startupSlotLookback).In(s.location)); !next.IsZero() && !next.After(now); next = schedule.Next(next) {
+		slot = next
+	}
+	return slot
+}
+
+// missedRun returns the most recent scheduled time of a job between its last recorded run and now
+func (s *Scheduler) missedRun(job models.ScheduledJob, lastRuns map[string]time.Time, now time.Time) (time.Time, bool) {
+	lastRun, ok := lastRuns[job.Name]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	schedule, err := cron.ParseStandard(job.Schedule)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var missed time.Time
+	for next := schedule.Next(lastRun.In(s.location)); !next.IsZero() && next.Before(now); next = schedule.Next(next) {
+		missed = next
+	}
+	return missed, !missed.IsZero()
+}
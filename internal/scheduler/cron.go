@@ -1,6 +1,8 @@
 package scheduler
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -8,28 +10,71 @@ import (
 	"time"
 
 	"github.com/hengky/news-scrapping/internal/ai"
+	"github.com/hengky/news-scrapping/internal/archive"
+	"github.com/hengky/news-scrapping/internal/category"
 	"github.com/hengky/news-scrapping/internal/config"
 	"github.com/hengky/news-scrapping/internal/discord"
+	"github.com/hengky/news-scrapping/internal/lock"
+	"github.com/hengky/news-scrapping/internal/ops"
 	"github.com/hengky/news-scrapping/internal/scraper"
+	"github.com/hengky/news-scrapping/internal/storage"
+	"github.com/hengky/news-scrapping/internal/trends"
+	"github.com/hengky/news-scrapping/internal/watchlist"
+	"github.com/hengky/news-scrapping/internal/websub"
 	"github.com/hengky/news-scrapping/pkg/models"
 	"github.com/robfig/cron/v3"
 )
 
+// maxJobHistory is the number of recent job records returned by GetJobHistory
+const maxJobHistory = 50
+
+// poolWindow is how far back the daily job looks into the polled article pool
+const poolWindow = 24 * time.Hour
+
+// weeklyWindow is the period covered by the weekly digest
+const weeklyWindow = 7 * 24 * time.Hour
+
+// archiveTimeout bounds the upload of one digest to object storage
+const archiveTimeout = time.Minute
+
+// ErrJobRunning is returned when a job of the same type is already running
+var ErrJobRunning = errors.New("job is already running")
+
 // Scheduler handles scheduled tasks
 type Scheduler struct {
-	cron          *cron.Cron
-	config        *config.Config
-	scraper       *scraper.Scraper
-	aiProcessor   *ai.Processor
-	discord       *discord.WebhookClient
-	discordGlobal *discord.WebhookClient
-	jobStatus     *models.JobStatus
-	mu            sync.RWMutex
-	running       bool
-}
-
-// New creates a new scheduler
-func New(cfg *config.Config) *Scheduler {
+	cron        *cron.Cron
+	location    *time.Location
+	config      *config.Config // Current configuration, replaced by Reload; read through cfg()
+	scraper     *scraper.Scraper
+	categories  *category.Registry
+	store       *storage.Store
+	trends      *trends.Tracker // nil disables developing story annotations
+	aiProcessor *ai.Processor
+	discord     *discord.WebhookClient
+	watcher     *watchlist.Watcher           // Matches nothing when WATCHLIST is empty
+	ops         *ops.Notifier                // Error notifications, deduplicated per error signature
+	locker      lock.Locker                  // nil when running a single replica
+	archiver    *archive.Archiver            // nil disables the digest archive
+	progress    *progressHub                 // Job progress events for streaming clients
+	websub      *websub.Manager              // nil disables WebSub push subscriptions
+	jobStatus   *models.JobStatus            // Status of the most recent job of any type
+	statuses    map[string]*models.JobStatus // Status keyed by job type
+	entries     map[string]cron.EntryID      // Cron entries keyed by job name
+	specs       map[string]string            // Current cron expressions keyed by job name
+	defaults    map[string]string            // Configured cron expressions keyed by job name
+	jobFuncs    map[string]func()            // Cron functions keyed by job name
+	runners     map[string]jobRunner         // Job runners keyed by job name, used for catch-up runs
+	mu          sync.RWMutex
+	reloadMu    sync.Mutex      // Serializes configuration reloads
+	outboxMu    sync.Mutex      // Held while the outbox worker retries deliveries
+	pollEntry   cron.EntryID    // Cron entry of the polling job, 0 when polling is off
+	running     map[string]bool // Job types currently running
+	polling     bool
+	paused      bool
+}
+
+// New creates a new scheduler for the given news categories
+func New(cfg *config.Config, categories *category.Registry, store *storage.Store) *Scheduler {
 	// Create timezone location
 	location, err := time.LoadLocation(cfg.Timezone)
 	if err != nil {
@@ -41,7 +86,7 @@ func New(cfg *config.Config) *Scheduler {
 	c := cron.New(cron.WithLocation(location))
 
 	// Initialize components
-	scraperInstance := scraper.New()
+	scraperInstance := scraper.NewWithConfig(cfg, categories)
 
 	aiProcessor, err := ai.NewProcessor(cfg)
 	if err != nil {
@@ -49,33 +94,168 @@ func New(cfg *config.Config) *Scheduler {
 	}
 
 	discordClient := discord.New(cfg.DiscordWebhook)
-	discordGlobalClient := discord.New(cfg.DiscordWebhookGlobal)
-
-	return &Scheduler{
-		cron:          c,
-		config:        cfg,
-		scraper:       scraperInstance,
-		aiProcessor:   aiProcessor,
-		discord:       discordClient,
-		discordGlobal: discordGlobalClient,
+	discordClient.SetThreadOptions(threadOptions(cfg))
+
+	// Send instant alerts for watchlist matches as soon as articles are scraped. The watcher
+	// is always installed so a reload can enable it.
+	watcher := watchlist.New(cfg.WatchlistTerms, discord.New(cfg.WatchlistWebhook))
+	scraperInstance.SetItemObserver(watcher.Observe)
+	if terms := watcher.Terms(); len(terms) > 0 {
+		log.Printf("Watchlist enabled with %d terms", len(terms))
+	}
+
+	// Claim scheduled runs in Redis so only one replica delivers each digest
+	var locker lock.Locker
+	if cfg.LockRedisURL != "" {
+		redisLocker, err := lock.NewRedis(cfg.LockRedisURL, time.Duration(cfg.LockTTLMinutes)*time.Minute)
+		if err != nil {
+			log.Fatalf("Failed to create scheduler lock: %v", err)
+		}
+		locker = redisLocker
+		log.Println("Distributed scheduler lock enabled")
+	}
+
+	// Archive every digest to object storage as an audit trail
+	var archiver *archive.Archiver
+	if cfg.ArchiveURL != "" {
+		archiver, err = archive.New(context.Background(), cfg)
+		if err != nil {
+			log.Fatalf("Failed to create digest archiver: %v", err)
+		}
+		log.Printf("Digest archive enabled at %s", cfg.ArchiveURL)
+	}
+
+	var tracker *trends.Tracker
+	if cfg.TrendWindowDays > 0 {
+		tracker = trends.New(store, location, cfg.TrendWindowDays)
+	}
+
+	s := &Scheduler{
+		cron:        c,
+		location:    location,
+		config:      cfg,
+		scraper:     scraperInstance,
+		categories:  categories,
+		store:       store,
+		trends:      tracker,
+		aiProcessor: aiProcessor,
+		discord:     discordClient,
+		watcher:     watcher,
+		ops:         ops.New(cfg.OpsWebhook, opsAlertInterval(cfg)),
+		locker:      locker,
+		archiver:    archiver,
+		progress:    newProgressHub(),
+		entries:     make(map[string]cron.EntryID),
+		specs:       make(map[string]string),
+		defaults:    make(map[string]string),
+		jobFuncs:    make(map[string]func()),
+		runners:     make(map[string]jobRunner),
+		running:     make(map[string]bool),
+		statuses:    initialStatuses(categories, cfg),
 		jobStatus: &models.JobStatus{
 			Status:    "initialized",
 			NewsCount: 0,
-			NextRun:   "08:00 WIB daily",
+			NextRun:   cfg.NewsSchedule,
 		},
 	}
+
+	// Let feeds with a WebSub hub push new items into the article pool
+	if cfg.WebSubCallbackURL != "" {
+		s.websub = websub.New(cfg, categories, store, scraperInstance, s.ingestPushedFeed)
+		log.Printf("WebSub subscriptions enabled with callbacks at %s", cfg.WebSubCallbackURL)
+	}
+
+	return s
+}
+
+// initialStatuses creates an initialized status for the daily and weekly job of every category
+func initialStatuses(categories *category.Registry, cfg *config.Config) map[string]*models.JobStatus {
+	statuses := make(map[string]*models.JobStatus)
+	for _, cat := range categories.All() {
+		statuses[cat.Name] = &models.JobStatus{Status: "initialized"}
+		if cfg.WeeklyDigestSchedule != "" {
+			statuses[weeklyJobType(cat.Name)] = &models.JobStatus{Status: "initialized"}
+		}
+	}
+	return statuses
+}
+
+// threadOptions maps the configuration to the Discord digest thread options
+func threadOptions(cfg *config.Config) discord.ThreadOptions {
+	return discord.ThreadOptions{
+		Mode:       cfg.DiscordThreadMode,
+		NameFormat: cfg.DiscordThreadName,
+		BotToken:   cfg.DiscordBotToken,
+	}
+}
+
+// opsAlertInterval is the minimum time between notifications of the same error
+func opsAlertInterval(cfg *config.Config) time.Duration {
+	return time.Duration(cfg.OpsAlertInterval) * time.Minute
+}
+
+// weeklyJobType is the job type used for a category's weekly digest
+func weeklyJobType(newsType string) string {
+	return newsType + "-weekly"
 }
 
 // Start starts the scheduler
 func (s *Scheduler) Start() {
-	// Schedule daily job at 08:00 WIB
-	_, err := s.cron.AddFunc("0 8 * * *", s.runNewsJob)
-	if err != nil {
-		log.Fatalf("Failed to schedule news job: %v", err)
+	state := s.store.SchedulerState()
+	s.mu.Lock()
+	s.paused = state.Paused
+	s.mu.Unlock()
+
+	for _, job := range s.jobSpecs(s.config, s.categories) {
+		s.addScheduledJob(job.name, job.spec, job.runner, state)
+	}
+
+	if cleanupEnabled(s.config) && s.config.RawRetentionDays > 0 && s.config.RawRetentionDays < s.config.TrendWindowDays {
+		log.Printf("Warning: RAW_RETENTION_DAYS (%d) is shorter than TREND_WINDOW_DAYS (%d), older coverage will not be linked",
+			s.config.RawRetentionDays, s.config.TrendWindowDays)
+	}
+
+	if !s.ops.Enabled() {
+		log.Printf("Warning: OPS_WEBHOOK is not set, job failures will only be logged")
+	}
+
+	// Optionally re-resolve secret references so rotated secrets are picked up
+	if s.config.SecretRefreshMinutes > 0 {
+		spec := fmt.Sprintf("@every %dm", s.config.SecretRefreshMinutes)
+		if _, err := s.cron.AddFunc(spec, s.runSecretRefresh); err != nil {
+			log.Fatalf("Failed to schedule secret refresh: %v", err)
+		}
+		log.Printf("Secrets refreshed every %d minutes", s.config.SecretRefreshMinutes)
+	}
+
+	// Subscribe to WebSub hubs now and check for new sources and expiring leases every hour
+	if s.websub != nil {
+		if _, err := s.cron.AddFunc(websubSyncSpec, s.runWebSubSync); err != nil {
+			log.Fatalf("Failed to schedule WebSub subscription sync: %v", err)
+		}
+		go s.runWebSubSync()
+	}
+
+	// Retry digests whose delivery failed, starting with any left over from before a restart.
+	// The worker is always scheduled so a reload can enable it.
+	if _, err := s.cron.AddFunc(outboxSpec, s.runOutbox); err != nil {
+		log.Fatalf("Failed to schedule outbox worker: %v", err)
+	}
+	go s.runOutbox()
+
+	// Optionally poll feeds continuously to build up the article pool
+	if err := s.schedulePolling(s.config); err != nil {
+		log.Fatalf("Failed to schedule polling job: %v", err)
 	}
 
 	s.cron.Start()
-	log.Printf("Scheduler started - News job scheduled for 08:00 %s daily", s.config.Timezone)
+	log.Printf("Scheduler started - News job scheduled with %q (%s)", s.specs[jobDaily], s.config.Timezone)
+	if state.Paused {
+		log.Println("Scheduler is paused - scheduled runs are skipped until resumed")
+	}
+
+	// Run on start and catch up on runs missed while the service was down
+	go s.runStartupJobs(state)
 
 	// Update next run time
 	s.updateNextRunTime()
@@ -89,208 +269,669 @@ func (s *Scheduler) Stop() {
 	if s.aiProcessor != nil {
 		s.aiProcessor.Close()
 	}
+	if s.locker != nil {
+		s.locker.Close()
+	}
 	log.Println("Scheduler stopped")
 }
 
 // RunManualJob runs the AI news job manually (backward compatibility)
 func (s *Scheduler) RunManualJob() error {
-	return s.RunManualJobByType("ai")
+	return s.RunManualJobByType(category.DefaultName)
 }
 
 // RunManualJobByType runs the news job manually with specified type
 func (s *Scheduler) RunManualJobByType(newsType string) error {
+	return s.RunJobByType(newsType, models.TriggerManual)
+}
+
+// RunJobByType runs the news job for a type outside the schedule, recording what triggered it
+func (s *Scheduler) RunJobByType(newsType, trigger string) error {
+	newsType = s.categories.Resolve(newsType).Name
+	return s.runExclusive(newsType, func() error {
+		return s.executeNewsJobByType(newsType, trigger)
+	})
+}
+
+// runExclusive runs job unless a job of the same type is already running.
+// Jobs of different types run independently of each other.
+func (s *Scheduler) runExclusive(jobType string, job func() error) error {
 	s.mu.Lock()
-	if s.running {
+	if s.running[jobType] {
 		s.mu.Unlock()
-		return fmt.Errorf("job is already running")
+		return fmt.Errorf("%s %w", jobType, ErrJobRunning)
 	}
-	s.running = true
+	s.running[jobType] = true
+	status := s.statusFor(jobType)
+	status.Status = "running"
+	status.Error = ""
 	s.mu.Unlock()
 
 	defer func() {
 		s.mu.Lock()
-		s.running = false
+		delete(s.running, jobType)
 		s.mu.Unlock()
 	}()
 
-	return s.executeNewsJobByType(newsType)
+	return job()
+}
+
+// runNewsJob is the scheduled job function for the daily digest
+func (s *Scheduler) runNewsJob(trigger string, slot time.Time, claimed func()) {
+	s.runScheduledJob("news", slot, claimed, func() error {
+		return s.executeNewsJob(trigger)
+	})
+}
+
+// runCategoryJob is the scheduled job function for categories with their own schedule
+func (s *Scheduler) runCategoryJob(newsType, trigger string, slot time.Time, claimed func()) {
+	s.runScheduledJob(newsType+" news", slot, claimed, func() error {
+		return s.runExclusive(newsType, func() error {
+			return s.executeNewsJobByType(newsType, trigger)
+		})
+	})
+}
+
+// runWeeklyDigestJob is the scheduled job function for the weekly digest
+func (s *Scheduler) runWeeklyDigestJob(trigger string, slot time.Time, claimed func()) {
+	s.runScheduledJob("weekly digest", slot, claimed, func() error {
+		return s.executeWeeklyDigest(trigger)
+	})
+}
+
+// RunManualWeeklyDigest runs the weekly digest job manually
+func (s *Scheduler) RunManualWeeklyDigest() error {
+	return s.RunWeeklyDigest(models.TriggerManual)
+}
+
+// RunWeeklyDigest runs the weekly digest outside the schedule, recording what triggered it
+func (s *Scheduler) RunWeeklyDigest(trigger string) error {
+	return s.executeWeeklyDigest(trigger)
+}
+
+// runScheduledJob runs a scheduled job, reporting failures to the ops webhook.
+// The slot is the scheduled time being run, used to claim the run across replicas;
+// claimed, when set, is called once the job is going ahead.
+func (s *Scheduler) runScheduledJob(name string, slot time.Time, claimed func(), job func() error) {
+	if s.IsPaused() {
+		log.Printf("Scheduler paused, skipping scheduled %s job", name)
+		return
+	}
+
+	if !s.claimScheduledRun(name, slot) {
+		return
+	}
+	if claimed != nil {
+		claimed()
+	}
+
+	defer s.updateNextRunTime()
+
+	log.Printf("Starting scheduled %s job...", name)
+
+	if err := job(); err != nil {
+		log.Printf("Scheduled %s job failed: %v", name, err)
+
+		// Send error notification, unless the same error was reported recently
+		s.ops.Report(fmt.Sprintf("scheduled %s job", name), err)
+	} else {
+		log.Printf("Scheduled %s job completed successfully", name)
+	}
+}
+
+// claimScheduledRun claims the slot (scheduled minute) of a scheduled job in the distributed lock.
+// Without a lock every run is claimed. If the lock is unreachable the job still runs, since
+// a duplicate digest is better than a missing one.
+func (s *Scheduler) claimScheduledRun(name string, slotTime time.Time) bool {
+	if s.locker == nil {
+		return true
+	}
+
+	slot := strings.ReplaceAll(name, " ", "-") + ":" + slotTime.In(s.location).Format("2006-01-02T15:04")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	claimed, err := s.locker.Claim(ctx, slot)
+	if err != nil {
+		log.Printf("Warning: Could not claim scheduled %s job, running anyway: %v", name, err)
+		return true
+	}
+	if !claimed {
+		log.Printf("Scheduled %s job already claimed by another replica, skipping", name)
+	}
+	return claimed
 }
 
-// runNewsJob is the scheduled job function
-func (s *Scheduler) runNewsJob() {
+// runPollJob scrapes every category and adds new articles to the pool without AI curation
+func (s *Scheduler) runPollJob() {
+	if s.IsPaused() {
+		return
+	}
+
 	s.mu.Lock()
-	if s.running {
-		log.Println("News job already running, skipping this execution")
+	if s.polling {
 		s.mu.Unlock()
+		log.Println("Previous poll still running, skipping this poll")
 		return
 	}
-	s.running = true
+	s.polling = true
 	s.mu.Unlock()
 
 	defer func() {
 		s.mu.Lock()
-		s.running = false
+		s.polling = false
 		s.mu.Unlock()
-		s.updateNextRunTime()
 	}()
 
-	log.Println("Starting scheduled news job...")
-
-	if err := s.executeNewsJob(); err != nil {
-		log.Printf("Scheduled news job failed: %v", err)
-
-		// Send error notification to Discord
-		errorMsg := fmt.Sprintf("❌ **News Bot Error**\n\nScheduled job failed at %s\n\nError: %s",
-			time.Now().Format("2006-01-02 15:04:05 MST"), err.Error())
-
-		if discordErr := s.discord.SendSimpleMessage(errorMsg); discordErr != nil {
-			log.Printf("Failed to send error notification to Discord: %v", discordErr)
+	for _, cat := range s.categories.All() {
+		result, err := s.scraper.ScrapeNewsByTypeWithMetrics(context.Background(), cat.Name)
+		if err != nil {
+			log.Printf("Poll of %s news failed: %v", cat.Name, err)
+			continue
 		}
-	} else {
-		log.Println("Scheduled news job completed successfully")
+		added := s.storeArticles(cat.Name, result.News)
+		log.Printf("Poll of %s news: %d scraped, %d new", cat.Name, len(result.News), added)
 	}
 }
 
-// executeNewsJob executes the complete AI news processing pipeline (backward compatibility)
-func (s *Scheduler) executeNewsJob() error {
-	// Execute AI news first
-	aiErr := s.executeNewsJobByType("ai")
-	if aiErr != nil {
-		log.Printf("AI news job failed: %v", aiErr)
+// storeArticles adds scraped articles to the pool, logging (but not failing on) storage errors
+func (s *Scheduler) storeArticles(newsType string, items []models.NewsItem) int {
+	added, err := s.store.AddArticles(newsType, items)
+	if err != nil {
+		log.Printf("Failed to store %s articles: %v", newsType, err)
 	}
+	return added
+}
+
+// schedulePolling (re)schedules the polling job for the configured interval, removing it when polling is off
+func (s *Scheduler) schedulePolling(cfg *config.Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	// Execute Global news
-	globalErr := s.executeNewsJobByType("global")
-	if globalErr != nil {
-		log.Printf("Global news job failed: %v", globalErr)
+	if s.pollEntry != 0 {
+		s.cron.Remove(s.pollEntry)
+		s.pollEntry = 0
+	}
+	if cfg.PollIntervalMinutes <= 0 {
+		return nil
 	}
 
-	// Return error if both failed
-	if aiErr != nil && globalErr != nil {
-		return fmt.Errorf("both AI and Global news jobs failed - AI: %v, Global: %v", aiErr, globalErr)
+	id, err := s.cron.AddFunc(fmt.Sprintf("@every %dm", cfg.PollIntervalMinutes), s.runPollJob)
+	if err != nil {
+		return err
 	}
+	s.pollEntry = id
+	log.Printf("Polling mode enabled - feeds polled every %d minutes", cfg.PollIntervalMinutes)
+	return nil
+}
+
+// pollingEnabled reports whether continuous polling mode is on
+func (s *Scheduler) pollingEnabled() bool {
+	return s.cfg().PollIntervalMinutes > 0
+}
 
-	// Return specific error if one failed
-	if aiErr != nil {
-		return fmt.Errorf("AI news job failed: %v", aiErr)
+// poolEnabled reports whether articles reach the pool between runs, through polling or
+// WebSub pushes, so the daily job curates the pool instead of only its own scrape
+func (s *Scheduler) poolEnabled() bool {
+	return s.pollingEnabled() || s.websub != nil
+}
+
+// executeNewsJob executes the news processing pipeline for every category on the daily schedule
+func (s *Scheduler) executeNewsJob(trigger string) error {
+	var failures []string
+	for _, cat := range s.categories.All() {
+		if cat.Schedule != "" {
+			continue // Runs on its own schedule
+		}
+		err := s.runExclusive(cat.Name, func() error {
+			return s.executeNewsJobByType(cat.Name, trigger)
+		})
+		if errors.Is(err, ErrJobRunning) {
+			log.Printf("%s news job already running, skipping", cat.DisplayName)
+			continue
+		}
+		if err != nil {
+			log.Printf("%s news job failed: %v", cat.DisplayName, err)
+			failures = append(failures, fmt.Sprintf("%s news job failed: %v", cat.DisplayName, err))
+		}
 	}
-	if globalErr != nil {
-		return fmt.Errorf("Global news job failed: %v", globalErr)
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
 	}
 
 	return nil
 }
 
 // executeNewsJobByType executes the complete news processing pipeline for a specific type
-func (s *Scheduler) executeNewsJobByType(newsType string) error {
+func (s *Scheduler) executeNewsJobByType(newsType, trigger string) error {
+	cat := s.categories.Resolve(newsType)
+	newsType = cat.Name
 	startTime := time.Now()
+	record := &models.JobRecord{
+		ID:        fmt.Sprintf("%s-%d", newsType, startTime.UnixNano()),
+		Type:      newsType,
+		Trigger:   trigger,
+		StartedAt: startTime,
+	}
 
 	s.mu.Lock()
 	s.jobStatus.Status = "running"
 	s.jobStatus.Error = ""
 	s.mu.Unlock()
+	s.reportProgress(record, models.StageStarted, fmt.Sprintf("%s news job started", cat.DisplayName))
 
 	// Step 1: Scrape news from sources based on type
 	log.Printf("Step 1: Scraping %s news from sources...", newsType)
-	newsItems, err := s.scraper.ScrapeNewsByType(newsType)
-	if err != nil {
-		s.updateJobStatus("failed", 0, err.Error())
+	s.reportProgress(record, models.StageScraping, fmt.Sprintf("Scraping %d sources", len(cat.Sources)))
+	scrapeResult, err := s.scraper.ScrapeNewsByTypeWithMetrics(context.Background(), newsType)
+	record.Sources = scrapeResult.Sources
+	if err != nil && !s.poolEnabled() {
+		s.finishJob(record, "failed", 0, err.Error())
 		return fmt.Errorf("failed to scrape %s news: %w", newsType, err)
 	}
 
+	newsItems := scrapeResult.News
+	s.storeArticles(newsType, newsItems)
+
+	// With polling or WebSub, curate the pool accumulated over the last day instead of just this scrape
+	if s.poolEnabled() {
+		newsItems = s.store.ArticlesSince(newsType, startTime.Add(-poolWindow))
+		log.Printf("Using %d pooled %s news items from the last %v", len(newsItems), newsType, poolWindow)
+		if len(newsItems) == 0 && err != nil {
+			s.finishJob(record, "failed", 0, err.Error())
+			return fmt.Errorf("failed to scrape %s news: %w", newsType, err)
+		}
+	}
+
 	if len(newsItems) == 0 {
-		s.updateJobStatus("completed", 0, fmt.Sprintf("No %s news items found", newsType))
+		s.finishJob(record, "completed", 0, fmt.Sprintf("No %s news items found", newsType))
 		return fmt.Errorf("no %s news items scraped", newsType)
 	}
 
 	log.Printf("Scraped %d %s news items", len(newsItems), newsType)
+	record.ScrapedCount = len(newsItems)
 
 	// Step 2: Process with AI to get top 5
 	log.Printf("Step 2: Processing %s news with Gemini AI...", newsType)
-	newsResponse, err := s.aiProcessor.ProcessNewsItemsByType(newsItems, newsType)
+	s.reportProgress(record, models.StageCurating, fmt.Sprintf("Curating %d news items", len(newsItems)))
+	newsResponse, err := s.aiProcessor.ProcessNewsItemsForCategory(newsItems, cat)
 	if err != nil {
-		s.updateJobStatus("failed", 0, err.Error())
+		s.finishJob(record, "failed", 0, err.Error())
 		return fmt.Errorf("failed to process %s news with AI: %w", newsType, err)
 	}
 
 	if len(newsResponse.News) == 0 {
-		s.updateJobStatus("completed", 0, fmt.Sprintf("No relevant %s news found", newsType))
+		s.finishJob(record, "completed", 0, fmt.Sprintf("No relevant %s news found", newsType))
 		return fmt.Errorf("AI processing returned no %s news items", newsType)
 	}
 
 	log.Printf("AI selected %d top %s news items", len(newsResponse.News), newsType)
+	record.TokenUsage = newsResponse.TokenUsage
 
-	// Step 3: Send to Discord (use appropriate webhook)
-	log.Printf("Step 3: Sending %s news to Discord...", newsType)
-	var discordErr error
-	if newsType == "global" {
-		log.Printf("Using global Discord webhook for %s news", newsType)
-		discordErr = s.discordGlobal.SendNewsByType(newsResponse, newsType)
-	} else {
-		log.Printf("Using AI Discord webhook for %s news", newsType)
-		discordErr = s.discord.SendNewsByType(newsResponse, newsType)
+	// Optionally rewrite summaries from the full article text
+	if s.cfg().DeepSummary {
+		log.Printf("Summarizing %d %s articles from their full text...", len(newsResponse.News), newsType)
+		s.aiProcessor.DeepSummarize(context.Background(), newsResponse, s.scraper.FetchArticleText)
+	}
+
+	// Link stories that were already covered on earlier days
+	if s.trends != nil {
+		s.trends.Annotate(newsType, newsResponse.News, time.Now())
 	}
 
+	s.saveDigest(cat, models.DigestDaily, newsResponse)
+
+	// Step 3: Send to Discord (category webhook)
+	log.Printf("Step 3: Sending %s news to Discord...", newsType)
+	s.reportProgress(record, models.StageDelivering, fmt.Sprintf("Sending %d news items to Discord", len(newsResponse.News)))
+	curatedAt := time.Now()
+	var progress models.DeliveryProgress
+	discordErr := s.deliverDigest(cat, models.DigestDaily, newsResponse, curatedAt, &progress)
 	if discordErr != nil {
-		s.updateJobStatus("failed", len(newsResponse.News), discordErr.Error())
+		// Keep the curated digest for the outbox worker instead of losing it
+		if s.queueDelivery(record, cat, models.DigestDaily, newsResponse, curatedAt, progress, discordErr) {
+			s.finishJob(record, "queued", len(newsResponse.News), fmt.Sprintf("Delivery failed, queued for retry: %v", discordErr))
+			return nil
+		}
+		s.finishJob(record, "failed", len(newsResponse.News), discordErr.Error())
 		return fmt.Errorf("failed to send %s news to Discord: %w", newsType, discordErr)
 	}
 
 	// Update job status
-	s.updateJobStatus("success", len(newsResponse.News), "")
+	s.finishJob(record, "success", len(newsResponse.News), "")
 
 	duration := time.Since(startTime)
 	log.Printf("%s news job completed successfully in %v - sent %d news items to Discord",
-		strings.Title(newsType), duration, len(newsResponse.News))
+		cat.DisplayName, duration, len(newsResponse.News))
+
+	return nil
+}
+
+// executeWeeklyDigest curates the top stories of the past week for every category
+func (s *Scheduler) executeWeeklyDigest(trigger string) error {
+	var failures []string
+	for _, cat := range s.categories.All() {
+		err := s.runExclusive(weeklyJobType(cat.Name), func() error {
+			return s.executeWeeklyDigestForCategory(cat, trigger)
+		})
+		if errors.Is(err, ErrJobRunning) {
+			log.Printf("%s weekly digest already running, skipping", cat.DisplayName)
+			continue
+		}
+		if err != nil {
+			log.Printf("%s weekly digest failed: %v", cat.DisplayName, err)
+			failures = append(failures, fmt.Sprintf("%s weekly digest failed: %v", cat.DisplayName, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
 
 	return nil
 }
 
-// GetJobStatus returns the current job status
+// executeWeeklyDigestForCategory builds and sends the weekly digest for one category
+func (s *Scheduler) executeWeeklyDigestForCategory(cat *category.Category, trigger string) error {
+	startTime := time.Now()
+	record := &models.JobRecord{
+		ID:        fmt.Sprintf("%s-weekly-%d", cat.Name, startTime.UnixNano()),
+		Type:      weeklyJobType(cat.Name),
+		Trigger:   trigger,
+		StartedAt: startTime,
+	}
+
+	s.reportProgress(record, models.StageStarted, fmt.Sprintf("%s weekly digest started", cat.DisplayName))
+
+	items := s.weeklyItems(cat.Name, startTime)
+	record.ScrapedCount = len(items)
+	if len(items) == 0 {
+		s.finishJob(record, "completed", 0, fmt.Sprintf("No %s news from the past week", cat.Name))
+		log.Printf("Skipping %s weekly digest - no news from the past week", cat.Name)
+		return nil
+	}
+
+	log.Printf("Processing %d %s news items from the past week with Gemini AI...", len(items), cat.Name)
+	s.reportProgress(record, models.StageCurating, fmt.Sprintf("Curating %d news items from the past week", len(items)))
+	newsResponse, err := s.aiProcessor.ProcessWeeklyItemsForCategory(items, cat, s.cfg().WeeklyDigestMaxItems)
+	if err != nil {
+		s.finishJob(record, "failed", 0, err.Error())
+		return fmt.Errorf("failed to process weekly %s news with AI: %w", cat.Name, err)
+	}
+
+	if len(newsResponse.News) == 0 {
+		s.finishJob(record, "completed", 0, fmt.Sprintf("No weekly %s news selected", cat.Name))
+		return fmt.Errorf("AI processing returned no weekly %s news items", cat.Name)
+	}
+
+	record.TokenUsage = newsResponse.TokenUsage
+	s.saveDigest(cat, models.DigestWeekly, newsResponse)
+	s.reportProgress(record, models.StageDelivering, fmt.Sprintf("Sending %d news items to Discord", len(newsResponse.News)))
+
+	curatedAt := time.Now()
+	var progress models.DeliveryProgress
+	if err := s.deliverDigest(cat, models.DigestWeekly, newsResponse, curatedAt, &progress); err != nil {
+		if s.queueDelivery(record, cat, models.DigestWeekly, newsResponse, curatedAt, progress, err) {
+			s.finishJob(record, "queued", len(newsResponse.News), fmt.Sprintf("Delivery failed, queued for retry: %v", err))
+			return nil
+		}
+		s.finishJob(record, "failed", len(newsResponse.News), err.Error())
+		return fmt.Errorf("failed to send weekly %s digest to Discord: %w", cat.Name, err)
+	}
+
+	s.finishJob(record, "success", len(newsResponse.News), "")
+	log.Printf("%s weekly digest sent with %d news items in %v", cat.DisplayName, len(newsResponse.News), time.Since(startTime))
+
+	return nil
+}
+
+// weeklyItems returns the items that made the past week's daily digests, deduplicated by URL.
+// When no daily digests were stored, the raw article pool for the week is used instead.
+func (s *Scheduler) weeklyItems(newsType string, now time.Time) []models.NewsItem {
+	digests := s.store.Digests(newsType, models.DigestDaily, now.Add(-weeklyWindow), now)
+
+	var items []models.NewsItem
+	seen := make(map[string]bool)
+	for _, digest := range digests {
+		for _, item := range digest.News {
+			if seen[item.URL] {
+				continue
+			}
+			seen[item.URL] = true
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		items = s.store.ArticlesSince(newsType, now.Add(-weeklyWindow))
+	}
+
+	return items
+}
+
+// saveDigest persists a curated digest, logging (but not failing on) storage errors
+func (s *Scheduler) saveDigest(cat *category.Category, kind string, newsResponse *models.NewsResponse) {
+	now := time.Now()
+	digest := &models.Digest{
+		ID:         fmt.Sprintf("%s-%s-%d", cat.Name, kind, now.UnixNano()),
+		Category:   cat.Name,
+		Kind:       kind,
+		Date:       now.In(s.location).Format("2006-01-02"),
+		CreatedAt:  now,
+		News:       newsResponse.News,
+		TokenUsage: newsResponse.TokenUsage,
+	}
+
+	if err := s.store.SaveDigest(digest); err != nil {
+		log.Printf("Failed to store %s %s digest: %v", kind, cat.Name, err)
+	}
+
+	if s.archiver != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), archiveTimeout)
+		defer cancel()
+		if keys, err := s.archiver.ArchiveDigest(ctx, *digest, cat.DisplayName); err != nil {
+			log.Printf("Warning: Failed to archive %s %s digest: %v", kind, cat.Name, err)
+		} else {
+			log.Printf("Archived %s %s digest to %v", kind, cat.Name, keys)
+		}
+	}
+}
+
+// Categories returns the news category registry used by the scheduler
+func (s *Scheduler) Categories() *category.Registry {
+	return s.categories
+}
+
+// WebSub returns the WebSub subscription manager, or nil when WebSub is disabled
+func (s *Scheduler) WebSub() *websub.Manager {
+	return s.websub
+}
+
+// Scraper returns the scraper shared by scheduled jobs
+func (s *Scheduler) Scraper() *scraper.Scraper {
+	return s.scraper
+}
+
+// Processor returns the AI processor shared by scheduled jobs, including its curation cache
+func (s *Scheduler) Processor() *ai.Processor {
+	return s.aiProcessor
+}
+
+// LatestDigest returns the most recent stored digest of a type and kind, optionally for one date (YYYY-MM-DD)
+func (s *Scheduler) LatestDigest(newsType, kind, date string) (models.Digest, bool) {
+	return s.store.LatestDigest(newsType, kind, date)
+}
+
+// SearchArticles runs a keyword search over the stored article pool
+func (s *Scheduler) SearchArticles(query storage.SearchQuery) ([]models.SearchResult, int) {
+	return s.store.Search(query)
+}
+
+// Config returns the configuration as last loaded at startup or by Reload
+func (s *Scheduler) Config() *config.Config {
+	return s.cfg()
+}
+
+// cfg returns the current configuration. Callers should read it once per job so a
+// concurrent reload does not mix old and new settings.
+func (s *Scheduler) cfg() *config.Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// Location returns the timezone schedules and digest dates use
+func (s *Scheduler) Location() *time.Location {
+	return s.location
+}
+
+// GetJobStatus returns the status of the most recent job of any type.
+// The status is "running" while any job is running.
 func (s *Scheduler) GetJobStatus() *models.JobStatus {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	status := *s.jobStatus // Copy the status
+	status.Paused = s.paused
+	if len(s.running) > 0 {
+		status.Status = "running"
+	}
 	return &status
 }
 
-// IsRunning returns whether a job is currently running
+// GetJobStatuses returns the status of every job type, each with the next run of the job that covers it
+func (s *Scheduler) GetJobStatuses() map[string]models.JobStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make(map[string]models.JobStatus, len(s.statuses))
+	for jobType, status := range s.statuses {
+		copied := *status
+		copied.Paused = s.paused
+		if id, ok := s.entries[s.scheduledJobFor(jobType)]; ok {
+			if next := s.cron.Entry(id).Next; !next.IsZero() {
+				copied.NextRun = next.Format("2006-01-02 15:04:05 MST")
+			}
+		}
+		statuses[jobType] = copied
+	}
+	return statuses
+}
+
+// scheduledJobFor returns the name of the scheduled job that runs a job type
+func (s *Scheduler) scheduledJobFor(jobType string) string {
+	if strings.HasSuffix(jobType, "-weekly") {
+		return jobWeekly
+	}
+	if _, ok := s.entries[jobType]; ok {
+		return jobType // Category with its own schedule
+	}
+	return jobDaily
+}
+
+// GetJobHistory returns recent job records, newest first
+func (s *Scheduler) GetJobHistory() []models.JobRecord {
+	jobs, _ := s.store.Jobs("", 0, maxJobHistory)
+	return jobs
+}
+
+// ListJobs returns a page of persisted job records, newest first, along with the total count.
+// An empty jobType lists every type.
+func (s *Scheduler) ListJobs(jobType string, page, pageSize int) ([]models.JobRecord, int) {
+	return s.store.Jobs(jobType, (page-1)*pageSize, pageSize)
+}
+
+// IsRunning returns whether a job of any type is currently running
 func (s *Scheduler) IsRunning() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.running
+	return len(s.running) > 0
 }
 
-// updateJobStatus updates the job status
-func (s *Scheduler) updateJobStatus(status string, newsCount int, errorMsg string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// IsTypeRunning returns whether the news job for a type is currently running
+func (s *Scheduler) IsTypeRunning(newsType string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.running[s.categories.Resolve(newsType).Name]
+}
 
-	s.jobStatus.LastRun = time.Now()
-	s.jobStatus.Status = status
-	s.jobStatus.NewsCount = newsCount
-	s.jobStatus.Error = errorMsg
+// IsWeeklyDigestRunning returns whether the weekly digest of any category is currently running
+func (s *Scheduler) IsWeeklyDigestRunning() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, cat := range s.categories.All() {
+		if s.running[weeklyJobType(cat.Name)] {
+			return true
+		}
+	}
+	return false
 }
 
-// updateNextRunTime updates the next run time
-func (s *Scheduler) updateNextRunTime() {
+// statusFor returns the status of a job type, creating it if needed. Callers must hold s.mu.
+func (s *Scheduler) statusFor(jobType string) *models.JobStatus {
+	status, ok := s.statuses[jobType]
+	if !ok {
+		status = &models.JobStatus{Status: "initialized"}
+		s.statuses[jobType] = status
+	}
+	return status
+}
+
+// updateJobStatus updates both the overall status and the status of the job's type
+func (s *Scheduler) updateJobStatus(record *models.JobRecord) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Calculate next 08:00 in the configured timezone
-	location, err := time.LoadLocation(s.config.Timezone)
-	if err != nil {
-		location = time.UTC
+	for _, jobStatus := range []*models.JobStatus{s.jobStatus, s.statusFor(record.Type)} {
+		jobStatus.LastRun = record.FinishedAt
+		jobStatus.Status = record.Status
+		jobStatus.NewsCount = record.NewsCount
+		jobStatus.Error = record.Error
+		jobStatus.Sources = record.Sources
 	}
+}
 
-	now := time.Now().In(location)
-	next := time.Date(now.Year(), now.Month(), now.Day(), 8, 0, 0, 0, location)
+// finishJob records the outcome of a job run in both the job status and the persisted job history
+func (s *Scheduler) finishJob(record *models.JobRecord, status string, newsCount int, errorMsg string) {
+	record.FinishedAt = time.Now()
+	record.DurationMs = record.FinishedAt.Sub(record.StartedAt).Milliseconds()
+	record.Status = status
+	record.NewsCount = newsCount
+	record.Error = errorMsg
 
-	// If it's already past 08:00 today, schedule for tomorrow
-	if now.Hour() >= 8 {
-		next = next.Add(24 * time.Hour)
+	s.updateJobStatus(record)
+	s.reportProgress(record, models.StageFinished, fmt.Sprintf("Job %s with %d news items", status, newsCount))
+
+	if err := s.store.AddJob(*record); err != nil {
+		log.Printf("Failed to store job record %s: %v", record.ID, err)
+	}
+}
+
+// updateNextRunTime updates the next run time from the earliest scheduled digest or cleanup job
+func (s *Scheduler) updateNextRunTime() {
+	// Only digest and cleanup jobs count; the outbox, polling, secret refresh, and WebSub
+	// entries run every few minutes and would hide the next digest
+	var next time.Time
+	s.mu.RLock()
+	for _, id := range s.entries {
+		entry := s.cron.Entry(id)
+		if entry.Next.IsZero() {
+			continue
+		}
+		if next.IsZero() || entry.Next.Before(next) {
+			next = entry.Next
+		}
+	}
+	s.mu.RUnlock()
+
+	if next.IsZero() {
+		return
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.jobStatus.NextRun = next.Format("2006-01-02 15:04:05 MST")
 }
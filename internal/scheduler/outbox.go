@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/config"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// outboxSpec is how often undelivered digests are checked for a retry
+const outboxSpec = "@every 1m"
+
+// Retry backoff for undelivered digests: 1, 2, 4, ... minutes between attempts, at most an hour
+const (
+	outboxBaseDelay = time.Minute
+	outboxMaxDelay  = time.Hour
+)
+
+// outboxEnabled reports whether failed deliveries are queued for retry
+func outboxEnabled(cfg *config.Config) bool {
+	return cfg.OutboxMaxAgeHours > 0
+}
+
+// outboxDelay is the wait before the next delivery attempt after the given number of attempts
+func outboxDelay(attempts int) time.Duration {
+	delay := outboxBaseDelay
+	for i := 1; i < attempts && delay < outboxMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, outboxMaxDelay)
+}
+
+// deliverDigest sends a curated digest to its Discord webhook; date is when it was curated.
+// Progress records what was posted, so a retry can resume where a failed attempt stopped.
+func (s *Scheduler) deliverDigest(cat *category.Category, kind string, newsResponse *models.NewsResponse, date time.Time, progress *models.DeliveryProgress) error {
+	if kind == models.DigestWeekly {
+		return s.discord.SendWeeklyDigest(newsResponse, cat, s.cfg().WeeklyDigestWebhook, date, progress)
+	}
+	return s.discord.SendNewsForCategory(newsResponse, cat, date, progress)
+}
+
+// queueDelivery stores a digest whose delivery failed so the outbox worker can retry it without
+// scraping and curating again, along with what the failed attempt already posted. It reports
+// whether the digest was queued.
+func (s *Scheduler) queueDelivery(record *models.JobRecord, cat *category.Category, kind string, newsResponse *models.NewsResponse, date time.Time, progress models.DeliveryProgress, deliveryErr error) bool {
+	if !outboxEnabled(s.cfg()) {
+		return false
+	}
+
+	entry := models.OutboxEntry{
+		ID:          fmt.Sprintf("%s-%s-%d", cat.Name, kind, date.UnixNano()),
+		JobID:       record.ID,
+		Category:    cat.Name,
+		Kind:        kind,
+		CreatedAt:   date,
+		News:        newsResponse.News,
+		TokenUsage:  newsResponse.TokenUsage,
+		State:       models.OutboxPending,
+		Attempts:    1,
+		NextAttempt: time.Now().Add(outboxDelay(1)),
+		LastError:   deliveryErr.Error(),
+		Progress:    progress,
+	}
+	if err := s.store.SaveOutboxEntry(entry); err != nil {
+		log.Printf("Failed to queue %s %s digest for retry: %v", kind, cat.Name, err)
+		return false
+	}
+
+	log.Printf("Queued %s %s digest for retry at %s after delivery failed: %v",
+		kind, cat.Name, entry.NextAttempt.Format("15:04:05"), deliveryErr)
+	return true
+}
+
+// runOutbox retries the queued digests that are due, giving up on digests older than OUTBOX_MAX_AGE_HOURS
+func (s *Scheduler) runOutbox() {
+	// Skip this pass if the previous one is still sending
+	if !s.outboxMu.TryLock() {
+		return
+	}
+	defer s.outboxMu.Unlock()
+
+	cfg := s.cfg()
+	if !outboxEnabled(cfg) {
+		return
+	}
+	maxAge := time.Duration(cfg.OutboxMaxAgeHours) * time.Hour
+	for _, entry := range s.store.OutboxEntries() {
+		now := time.Now()
+		if entry.State != models.OutboxPending || now.Before(entry.NextAttempt) {
+			continue
+		}
+
+		// A digest that is too old is no longer news
+		if now.Sub(entry.CreatedAt) >= maxAge {
+			s.failDelivery(entry, fmt.Errorf("not delivered within %v: %s", maxAge, entry.LastError))
+			continue
+		}
+
+		cat, ok := s.categories.Get(entry.Category)
+		if !ok {
+			s.failDelivery(entry, fmt.Errorf("category %q no longer exists", entry.Category))
+			continue
+		}
+
+		newsResponse := &models.NewsResponse{News: entry.News, TokenUsage: entry.TokenUsage}
+		entry.Attempts++
+		if err := s.deliverDigest(cat, entry.Kind, newsResponse, entry.CreatedAt, &entry.Progress); err != nil {
+			entry.LastError = err.Error()
+			entry.NextAttempt = now.Add(outboxDelay(entry.Attempts))
+			if err := s.store.SaveOutboxEntry(entry); err != nil {
+				log.Printf("Failed to update outbox entry %s: %v", entry.ID, err)
+			}
+			log.Printf("Retry %d of %s %s digest failed, next attempt at %s: %v",
+				entry.Attempts, entry.Kind, entry.Category, entry.NextAttempt.Format("15:04:05"), err)
+			continue
+		}
+
+		if err := s.store.DeleteOutboxEntry(entry.ID); err != nil {
+			log.Printf("Failed to remove delivered outbox entry %s: %v", entry.ID, err)
+		}
+		log.Printf("Delivered queued %s %s digest after %d attempts (%v late)",
+			entry.Kind, entry.Category, entry.Attempts, now.Sub(entry.CreatedAt).Round(time.Second))
+	}
+}
+
+// failDelivery gives up on a queued digest and reports it to the ops webhook
+func (s *Scheduler) failDelivery(entry models.OutboxEntry, err error) {
+	now := time.Now()
+	entry.State = models.OutboxFailed
+	entry.FailedAt = &now
+	entry.LastError = err.Error()
+	if saveErr := s.store.SaveOutboxEntry(entry); saveErr != nil {
+		log.Printf("Failed to update outbox entry %s: %v", entry.ID, saveErr)
+	}
+
+	createdAt := entry.CreatedAt.In(s.location).Format("2006-01-02 15:04")
+	log.Printf("Giving up on %s %s digest from %s after %d attempts: %v",
+		entry.Kind, entry.Category, createdAt, entry.Attempts, err)
+	s.ops.Report(fmt.Sprintf("delivery of the %s %s digest from %s", entry.Kind, entry.Category, createdAt), err)
+}
+
+// Outbox returns the digests waiting for a delivery retry and those that permanently failed
+func (s *Scheduler) Outbox() []models.OutboxEntry {
+	return s.store.OutboxEntries()
+}
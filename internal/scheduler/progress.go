@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// progressBuffer is the number of events buffered per subscriber. A subscriber that falls this far
+// behind is disconnected, so it never waits for an event (such as finished) that was dropped.
+const progressBuffer = 32
+
+// progressHub fans job progress events out to subscribers
+type progressHub struct {
+	mu          sync.Mutex
+	subscribers map[chan models.JobProgress]struct{}
+}
+
+// newProgressHub creates an empty progress hub
+func newProgressHub() *progressHub {
+	return &progressHub{subscribers: make(map[chan models.JobProgress]struct{})}
+}
+
+// subscribe returns a channel of progress events and a function that closes it. The channel is
+// also closed when the subscriber falls too far behind.
+func (h *progressHub) subscribe() (<-chan models.JobProgress, func()) {
+	ch := make(chan models.JobProgress, progressBuffer)
+
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		// The channel may already be closed by publish
+		if _, ok := h.subscribers[ch]; ok {
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// publish sends an event to every subscriber without blocking the job, disconnecting
+// subscribers whose buffer is full
+func (h *progressHub) publish(event models.JobProgress) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("Warning: Disconnecting progress subscriber that fell %d events behind", progressBuffer)
+			delete(h.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// SubscribeProgress returns a channel receiving progress events of every job and a function
+// to stop receiving them
+func (s *Scheduler) SubscribeProgress() (<-chan models.JobProgress, func()) {
+	return s.progress.subscribe()
+}
+
+// reportProgress publishes the current stage of a job
+func (s *Scheduler) reportProgress(record *models.JobRecord, stage, message string) {
+	s.progress.publish(models.JobProgress{
+		JobID:        record.ID,
+		Type:         record.Type,
+		Trigger:      record.Trigger,
+		Stage:        stage,
+		Message:      message,
+		ScrapedCount: record.ScrapedCount,
+		NewsCount:    record.NewsCount,
+		Status:       record.Status,
+		Error:        record.Error,
+		Time:         time.Now(),
+	})
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/config"
+	"github.com/hengky/news-scrapping/internal/discord"
+	"github.com/robfig/cron/v3"
+)
+
+// Reload re-reads the configuration and the category definitions (sources, filter rules, and
+// prompts), re-resolves secret references, applies the new settings to the AI processor, watchlist,
+// and notifiers, and reschedules jobs. Server, storage, scraper, lock, and archive settings need a
+// restart. Nothing changes if the new configuration is invalid. Schedules changed through the
+// admin API still take precedence over configured ones.
+func (s *Scheduler) Reload() error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	categories, err := category.NewRegistry(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load news categories: %w", err)
+	}
+
+	jobs := s.jobSpecs(cfg, categories)
+	for _, job := range jobs {
+		if _, err := cron.ParseStandard(job.spec); err != nil {
+			return fmt.Errorf("invalid cron expression %q for %s job: %w", job.spec, job.name, err)
+		}
+	}
+
+	// Pick up a rotated Gemini API key and changed curation settings
+	if err := s.aiProcessor.SetConfig(cfg); err != nil {
+		return fmt.Errorf("failed to apply Gemini settings: %w", err)
+	}
+
+	s.categories.Replace(categories)
+	s.discord.SetThreadOptions(threadOptions(cfg))
+	s.ops.SetWebhook(cfg.OpsWebhook, opsAlertInterval(cfg))
+	s.watcher.Set(cfg.WatchlistTerms, discord.New(cfg.WatchlistWebhook))
+	if s.archiver != nil {
+		s.archiver.SetCredentials(cfg)
+	}
+
+	s.mu.Lock()
+	for _, id := range s.entries {
+		s.cron.Remove(id)
+	}
+	s.config = cfg
+	s.entries = make(map[string]cron.EntryID)
+	s.specs = make(map[string]string)
+	s.defaults = make(map[string]string)
+	s.jobFuncs = make(map[string]func())
+	s.runners = make(map[string]jobRunner)
+	s.mu.Unlock()
+
+	state := s.store.SchedulerState()
+	for _, job := range jobs {
+		s.addScheduledJob(job.name, job.spec, job.runner, state)
+	}
+	if err := s.schedulePolling(cfg); err != nil {
+		log.Printf("Warning: Failed to reschedule polling job: %v", err)
+	}
+	s.updateNextRunTime()
+
+	// Subscribe to the hubs of newly added sources
+	if s.websub != nil {
+		go s.runWebSubSync()
+	}
+
+	log.Printf("Configuration reloaded: %d categories, %d scheduled jobs", len(categories.Names()), len(jobs))
+	return nil
+}
+
+// runSecretRefresh fetches secret references again and applies rotated secrets to the components
+// that use them. Schedules and other settings are left as they are.
+func (s *Scheduler) runSecretRefresh() {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	current := s.cfg()
+	cfg, rotated, err := current.RefreshSecrets()
+	if err != nil {
+		log.Printf("Warning: Secret refresh failed, keeping the current secrets: %v", err)
+		return
+	}
+	if len(rotated) == 0 {
+		return
+	}
+
+	if err := s.aiProcessor.SetConfig(cfg); err != nil {
+		log.Printf("Warning: Secret refresh failed, keeping the current secrets: %v", err)
+		return
+	}
+	s.categories.ReplaceWebhooks(rotatedWebhooks(current, cfg))
+	s.discord.SetThreadOptions(threadOptions(cfg))
+	s.ops.SetWebhook(cfg.OpsWebhook, opsAlertInterval(cfg))
+	s.watcher.Set(cfg.WatchlistTerms, discord.New(cfg.WatchlistWebhook))
+	if s.archiver != nil {
+		s.archiver.SetCredentials(cfg)
+	}
+
+	s.mu.Lock()
+	s.config = cfg
+	s.mu.Unlock()
+
+	log.Printf("Secrets refreshed: %s rotated", strings.Join(rotated, ", "))
+}
+
+// rotatedWebhooks maps each category webhook that changed between two configurations to its new value
+func rotatedWebhooks(old, cfg *config.Config) map[string]string {
+	rotated := make(map[string]string)
+	pairs := [][2]string{
+		{old.DiscordWebhook, cfg.DiscordWebhook},
+		{old.DiscordWebhookGlobal, cfg.DiscordWebhookGlobal},
+		{old.DiscordWebhookLocal, cfg.DiscordWebhookLocal},
+		{old.DiscordWebhookCrypto, cfg.DiscordWebhookCrypto},
+	}
+	for name, override := range old.CategoryOverrides {
+		pairs = append(pairs, [2]string{override.Webhook, cfg.CategoryOverrides[name].Webhook})
+	}
+	for _, pair := range pairs {
+		if pair[0] != "" && pair[0] != pair[1] {
+			rotated[pair[0]] = pair[1]
+		}
+	}
+	return rotated
+}
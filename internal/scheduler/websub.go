@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/category"
+)
+
+// websubSyncSpec is how often WebSub subscriptions are checked for new sources and expiring leases
+const websubSyncSpec = "@every 1h"
+
+// websubSyncTimeout bounds one pass over every source's hub
+const websubSyncTimeout = 10 * time.Minute
+
+// runWebSubSync subscribes to the hubs of new sources and renews expiring leases
+func (s *Scheduler) runWebSubSync() {
+	ctx, cancel := context.WithTimeout(context.Background(), websubSyncTimeout)
+	defer cancel()
+
+	s.websub.Sync(ctx)
+}
+
+// ingestPushedFeed adds the items of a feed document pushed by a WebSub hub to the article pool
+func (s *Scheduler) ingestPushedFeed(cat *category.Category, source category.Source, body []byte) (int, error) {
+	items, err := s.scraper.ParseFeed(body, source, cat)
+	if err != nil {
+		return 0, err
+	}
+	return s.storeArticles(cat.Name, items), nil
+}
@@ -0,0 +1,64 @@
+package scraper
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// maxExtractedBytes caps the size of the article text handed to the AI
+const maxExtractedBytes = 12000
+
+// ExtractText returns the readable body text of an article page, preferring the
+// <article> or <main> element and skipping navigation, scripts, and other boilerplate.
+func ExtractText(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return ""
+	}
+
+	doc.Find("script, style, noscript, nav, header, footer, aside, form, figure, iframe").Remove()
+
+	root := doc.Find("article").First()
+	if root.Length() == 0 {
+		root = doc.Find("main").First()
+	}
+	if root.Length() == 0 {
+		root = doc.Find("body")
+	}
+
+	var paragraphs []string
+	size := 0
+	root.Find("p, h2, h3, li").EachWithBreak(func(_ int, sel *goquery.Selection) bool {
+		text := cleanText(sel.Text())
+		// Skip short fragments such as bylines, captions, and share buttons
+		if len(text) < 40 {
+			return true
+		}
+		paragraphs = append(paragraphs, text)
+		size += len(text)
+		return size < maxExtractedBytes
+	})
+
+	text := strings.Join(paragraphs, "\n")
+	if len(text) > maxExtractedBytes {
+		// Cut on a rune boundary so multi-byte characters are not split
+		cut := maxExtractedBytes
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut]
+	}
+	return text
+}
+
+// FetchArticleText downloads an article page and extracts its readable text
+func (s *Scraper) FetchArticleText(ctx context.Context, articleURL string) (string, error) {
+	html, err := s.FetchArticle(ctx, articleURL)
+	if err != nil {
+		return "", err
+	}
+	return ExtractText(html), nil
+}
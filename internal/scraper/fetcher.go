@@ -0,0 +1,117 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/config"
+)
+
+// DefaultUserAgent identifies the bot to publishers when no User-Agent is configured
+const DefaultUserAgent = "NewsScrappingBot/1.0 (+https://github.com/hengliuu/news-scrapping)"
+
+// maxArticleBytes caps how much of an article page is read into memory
+const maxArticleBytes = 2 << 20
+
+// ErrDisallowedByRobots is returned when robots.txt forbids fetching a URL
+var ErrDisallowedByRobots = errors.New("fetch disallowed by robots.txt")
+
+// fetcher performs outbound HTTP requests for feeds and article pages
+type fetcher struct {
+	client    *http.Client
+	userAgent string
+	robots    *robotsCache // nil disables robots.txt checks
+}
+
+// newFetcher creates a fetcher from config
+func newFetcher(cfg *config.Config) *fetcher {
+	f := defaultFetcher()
+	f.client.Transport = newTransport(cfg.ScraperProxyURL)
+	if cfg.ScraperUserAgent != "" {
+		f.userAgent = cfg.ScraperUserAgent
+	}
+	if cfg.RespectRobotsTxt {
+		f.robots = newRobotsCache(f.client, f.userAgent)
+	} else {
+		f.robots = nil
+	}
+	return f
+}
+
+// defaultFetcher creates a fetcher with the default User-Agent and robots.txt checks enabled
+func defaultFetcher() *fetcher {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	return &fetcher{
+		client:    client,
+		userAgent: DefaultUserAgent,
+		robots:    newRobotsCache(client, DefaultUserAgent),
+	}
+}
+
+// newTransport builds the transport for scraping traffic. An explicit proxy URL takes
+// precedence; otherwise HTTP_PROXY/HTTPS_PROXY/NO_PROXY from the environment apply.
+func newTransport(proxyURL string) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	if proxyURL != "" {
+		parsed, err := url.Parse(proxyURL)
+		if err != nil {
+			log.Printf("Warning: Invalid scraper proxy URL, falling back to environment proxy settings: %v", err)
+			return transport
+		}
+		transport.Proxy = http.ProxyURL(parsed)
+		log.Printf("Scraper traffic routed through proxy %s", parsed.Redacted())
+	}
+
+	return transport
+}
+
+// get issues a GET request with the bot User-Agent
+func (f *fetcher) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+
+	return f.client.Do(req)
+}
+
+// fetchArticle downloads an article page after checking robots.txt
+func (f *fetcher) fetchArticle(ctx context.Context, articleURL string) (string, error) {
+	if f.robots != nil {
+		allowed, err := f.robots.allowed(ctx, articleURL)
+		if err != nil {
+			return "", fmt.Errorf("failed to check robots.txt for %s: %w", articleURL, err)
+		}
+		if !allowed {
+			return "", fmt.Errorf("%s: %w", articleURL, ErrDisallowedByRobots)
+		}
+	}
+
+	resp, err := f.get(ctx, articleURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch article %s: %w", articleURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("article %s returned status %d", articleURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxArticleBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to read article %s: %w", articleURL, err)
+	}
+
+	return string(body), nil
+}
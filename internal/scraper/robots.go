@@ -0,0 +1,221 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How long parsed robots.txt files are cached per host
+const (
+	robotsCacheTTL      = 6 * time.Hour
+	robotsErrorCacheTTL = 10 * time.Minute
+	maxRobotsBytes      = 512 << 10
+)
+
+// robotsRule is a single Allow/Disallow line
+type robotsRule struct {
+	allow bool
+	path  string
+}
+
+// robotsEntry holds the rules that apply to our bot for one host
+type robotsEntry struct {
+	rules       []robotsRule
+	disallowAll bool
+	expiresAt   time.Time
+}
+
+// robotsCache fetches, parses, and caches robots.txt per host
+type robotsCache struct {
+	client    *http.Client
+	userAgent string
+	agent     string // lowercase product token used to match User-agent groups
+	mu        sync.Mutex
+	entries   map[string]*robotsEntry
+}
+
+// newRobotsCache creates a robots.txt cache for the given User-Agent
+func newRobotsCache(client *http.Client, userAgent string) *robotsCache {
+	// Match groups on the product token, e.g. "NewsScrappingBot" from "NewsScrappingBot/1.0 (...)"
+	agent := userAgent
+	if idx := strings.IndexAny(agent, "/ "); idx > 0 {
+		agent = agent[:idx]
+	}
+
+	return &robotsCache{
+		client:    client,
+		userAgent: userAgent,
+		agent:     strings.ToLower(agent),
+		entries:   make(map[string]*robotsEntry),
+	}
+}
+
+// allowed reports whether robots.txt permits fetching rawURL
+func (r *robotsCache) allowed(ctx context.Context, rawURL string) (bool, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false, fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Host == "" {
+		return false, fmt.Errorf("URL has no host: %s", rawURL)
+	}
+
+	entry := r.entry(ctx, u)
+	if entry.disallowAll {
+		return false, nil
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	// Longest matching rule wins; Allow wins ties
+	matched := -1
+	allowed := true
+	for _, rule := range entry.rules {
+		if !robotsPathMatch(rule.path, path) {
+			continue
+		}
+		if len(rule.path) > matched || (len(rule.path) == matched && rule.allow) {
+			matched = len(rule.path)
+			allowed = rule.allow
+		}
+	}
+
+	return allowed, nil
+}
+
+// entry returns the cached rules for a host, fetching robots.txt when missing or expired
+func (r *robotsCache) entry(ctx context.Context, u *url.URL) *robotsEntry {
+	key := u.Scheme + "://" + u.Host
+
+	r.mu.Lock()
+	entry, ok := r.entries[key]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry
+	}
+
+	entry = r.fetch(ctx, key)
+
+	r.mu.Lock()
+	r.entries[key] = entry
+	r.mu.Unlock()
+
+	return entry
+}
+
+// fetch downloads and parses robots.txt for a scheme://host key
+func (r *robotsCache) fetch(ctx context.Context, key string) *robotsEntry {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, key+"/robots.txt", nil)
+	if err != nil {
+		return &robotsEntry{disallowAll: true, expiresAt: time.Now().Add(robotsErrorCacheTTL)}
+	}
+	req.Header.Set("User-Agent", r.userAgent)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		// Be conservative when the host is unreachable and try again later
+		log.Printf("Failed to fetch robots.txt from %s: %v", key, err)
+		return &robotsEntry{disallowAll: true, expiresAt: time.Now().Add(robotsErrorCacheTTL)}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		log.Printf("robots.txt from %s returned status %d, treating host as disallowed", key, resp.StatusCode)
+		return &robotsEntry{disallowAll: true, expiresAt: time.Now().Add(robotsErrorCacheTTL)}
+	case resp.StatusCode >= 400:
+		// No robots.txt means everything is allowed
+		return &robotsEntry{expiresAt: time.Now().Add(robotsCacheTTL)}
+	}
+
+	rules := parseRobots(io.LimitReader(resp.Body, maxRobotsBytes), r.agent)
+	return &robotsEntry{rules: rules, expiresAt: time.Now().Add(robotsCacheTTL)}
+}
+
+// parseRobots extracts the rules of the group matching agent, falling back to the "*" group
+func parseRobots(body io.Reader, agent string) []robotsRule {
+	var specific, wildcard []robotsRule
+	var hasSpecific bool
+
+	var groupAgents []string
+	inRules := false
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// A user-agent line after rules starts a new group
+			if inRules {
+				groupAgents = nil
+				inRules = false
+			}
+			groupAgents = append(groupAgents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			// An empty Disallow allows everything but still marks the group as present
+			empty := key == "disallow" && value == ""
+			rule := robotsRule{allow: key == "allow", path: value}
+			for _, ga := range groupAgents {
+				switch {
+				case ga == "*":
+					if !empty {
+						wildcard = append(wildcard, rule)
+					}
+				case ga != "" && (strings.Contains(agent, ga) || strings.Contains(ga, agent)):
+					hasSpecific = true
+					if !empty {
+						specific = append(specific, rule)
+					}
+				}
+			}
+		}
+	}
+
+	if hasSpecific {
+		return specific
+	}
+	return wildcard
+}
+
+// robotsPathMatch matches a robots.txt path pattern supporting "*" wildcards and a trailing "$" anchor
+func robotsPathMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(path)
+}
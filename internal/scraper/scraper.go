@@ -1,77 +1,182 @@
 package scraper
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"sync"
+	"time"
 
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/config"
 	"github.com/hengky/news-scrapping/pkg/models"
+	"github.com/mmcdole/gofeed"
+)
+
+// Default worker pool settings used when no configuration is provided
+const (
+	defaultConcurrency  = 4
+	defaultFetchTimeout = 30 * time.Second
 )
 
 // Scraper handles news scraping operations
 type Scraper struct {
-	aiSources     []NewsSource
-	globalSources []NewsSource
+	categories   *category.Registry
+	concurrency  int
+	fetchTimeout time.Duration
+	fetcher      *fetcher
+	observe      func(models.NewsItem)
 }
 
-// New creates a new scraper instance
-func New() *Scraper {
+// New creates a new scraper instance for the given categories
+func New(categories *category.Registry) *Scraper {
 	return &Scraper{
-		aiSources:     GetAINewsSources(),
-		globalSources: GetGlobalNewsSources(),
+		categories:   categories,
+		concurrency:  defaultConcurrency,
+		fetchTimeout: defaultFetchTimeout,
+		fetcher:      defaultFetcher(),
+	}
+}
+
+// NewWithConfig creates a new scraper instance with worker pool settings from config
+func NewWithConfig(cfg *config.Config, categories *category.Registry) *Scraper {
+	s := New(categories)
+	if cfg.ScrapeConcurrency > 0 {
+		s.concurrency = cfg.ScrapeConcurrency
+	}
+	if cfg.ScrapeTimeoutSeconds > 0 {
+		s.fetchTimeout = time.Duration(cfg.ScrapeTimeoutSeconds) * time.Second
+	}
+	s.fetcher = newFetcher(cfg)
+	return s
+}
+
+// SetItemObserver registers a function called for every recent scraped item before
+// category filtering. It is called concurrently from scraping workers.
+func (s *Scraper) SetItemObserver(observe func(models.NewsItem)) {
+	s.observe = observe
+}
+
+// FetchArticle downloads the HTML of an article page using the bot User-Agent.
+// When robots.txt checking is enabled, disallowed URLs return ErrDisallowedByRobots.
+func (s *Scraper) FetchArticle(ctx context.Context, articleURL string) (string, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, s.fetchTimeout)
+	defer cancel()
+
+	return s.fetcher.fetchArticle(fetchCtx, articleURL)
+}
+
+// Fetch issues a GET request with the bot User-Agent through the scraper's client and proxy
+func (s *Scraper) Fetch(ctx context.Context, rawURL string) (*http.Response, error) {
+	return s.fetcher.get(ctx, rawURL)
+}
+
+// ParseFeed converts a feed document pushed or fetched for a source into news items,
+// applying the same recency and category filters as scraping. Observers see every recent item.
+func (s *Scraper) ParseFeed(data []byte, source NewsSource, cat *category.Category) ([]models.NewsItem, error) {
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse feed from %s: %w", source.Name, err)
 	}
+
+	var metrics models.SourceMetrics
+	return feedItems(feed, source, cat, s.observe, &metrics), nil
 }
 
 // ScrapeAllSources scrapes news from all AI sources (backward compatibility)
 func (s *Scraper) ScrapeAllSources() ([]models.NewsItem, error) {
-	return s.ScrapeNewsByType("ai")
+	return s.ScrapeNewsByType(category.DefaultName)
 }
 
 // ScrapeNewsByType scrapes news from sources based on type
 func (s *Scraper) ScrapeNewsByType(newsType string) ([]models.NewsItem, error) {
-	var sources []NewsSource
-	
-	// Select sources based on type
-	switch newsType {
-	case "global":
-		sources = s.globalSources
-	case "ai":
-		fallthrough
-	default:
-		sources = s.aiSources
-		newsType = "ai" // Normalize the type
+	return s.ScrapeNewsByTypeWithContext(context.Background(), newsType)
+}
+
+// ScrapeResult holds the articles from a scraping run along with per-source metrics
+type ScrapeResult struct {
+	News    []models.NewsItem
+	Sources []models.SourceMetrics
+}
+
+// ScrapeNewsByTypeWithContext scrapes news from sources based on type using a bounded worker pool.
+// Each fetch gets its own timeout derived from ctx.
+func (s *Scraper) ScrapeNewsByTypeWithContext(ctx context.Context, newsType string) ([]models.NewsItem, error) {
+	result, err := s.ScrapeNewsByTypeWithMetrics(ctx, newsType)
+	if err != nil {
+		return nil, err
 	}
+	return result.News, nil
+}
+
+// ScrapeNewsByTypeWithMetrics scrapes news like ScrapeNewsByTypeWithContext and also reports
+// per-source metrics. The result is non-nil even when an error is returned so callers can
+// still inspect which sources failed.
+func (s *Scraper) ScrapeNewsByTypeWithMetrics(ctx context.Context, newsType string) (*ScrapeResult, error) {
+	// Select sources based on category, unknown types fall back to the default category
+	cat := s.categories.Resolve(newsType)
+	newsType = cat.Name // Normalize the type
+	sources := cat.Sources
 
 	var allNews []models.NewsItem
+	var allMetrics []models.SourceMetrics
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
 	// Channel to collect errors
 	errChan := make(chan error, len(sources))
 
-	// Scrape from all sources concurrently
-	for _, source := range sources {
+	// Feed sources to a bounded pool of workers
+	jobs := make(chan NewsSource)
+	workers := s.concurrency
+	if workers > len(sources) {
+		workers = len(sources)
+	}
+
+	for i := 0; i < workers; i++ {
 		wg.Add(1)
-		go func(src NewsSource) {
+		go func() {
 			defer wg.Done()
 
-			news, err := ScrapeNewsFromSource(src, newsType)
-			if err != nil {
-				log.Printf("Error scraping from %s: %v", src.Name, err)
-				errChan <- fmt.Errorf("failed to scrape %s: %w", src.Name, err)
-				return
-			}
+			for src := range jobs {
+				fetchCtx, cancel := context.WithTimeout(ctx, s.fetchTimeout)
+				news, metrics, err := scrapeSource(fetchCtx, s.fetcher, src, cat, s.observe)
+				cancel()
 
-			// Thread-safe append
-			mu.Lock()
-			allNews = append(allNews, news...)
-			mu.Unlock()
+				mu.Lock()
+				allMetrics = append(allMetrics, metrics)
+				mu.Unlock()
 
-			log.Printf("Successfully scraped %d articles from %s", len(news), src.Name)
-		}(source)
+				if err != nil {
+					log.Printf("Error scraping from %s: %v", src.Name, err)
+					errChan <- fmt.Errorf("failed to scrape %s: %w", src.Name, err)
+					continue
+				}
+
+				// Thread-safe append
+				mu.Lock()
+				allNews = append(allNews, news...)
+				mu.Unlock()
+
+				log.Printf("Successfully scraped %d articles from %s", len(news), src.Name)
+			}
+		}()
 	}
 
-	// Wait for all goroutines to complete
+	// Stop handing out work once the parent context is cancelled
+dispatch:
+	for _, source := range sources {
+		select {
+		case jobs <- source:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+
+	// Wait for all workers to complete
 	wg.Wait()
 	close(errChan)
 
@@ -81,8 +186,13 @@ func (s *Scraper) ScrapeNewsByType(newsType string) ([]models.NewsItem, error) {
 		errors = append(errors, err)
 	}
 
+	result := &ScrapeResult{News: allNews, Sources: allMetrics}
+
 	if len(allNews) == 0 {
-		return nil, fmt.Errorf("no news articles scraped from any source. Errors: %v", errors)
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("scraping cancelled: %w", ctx.Err())
+		}
+		return result, fmt.Errorf("no news articles scraped from any source. Errors: %v", errors)
 	}
 
 	// Log summary
@@ -91,22 +201,15 @@ func (s *Scraper) ScrapeNewsByType(newsType string) ([]models.NewsItem, error) {
 		log.Printf("Encountered %d errors during scraping", len(errors))
 	}
 
-	return allNews, nil
+	return result, nil
 }
 
-// GetSourceCount returns the number of AI sources (backward compatibility)
+// GetSourceCount returns the number of sources in the default category (backward compatibility)
 func (s *Scraper) GetSourceCount() int {
-	return len(s.aiSources)
+	return s.GetSourceCountByType(category.DefaultName)
 }
 
 // GetSourceCountByType returns the number of sources for a specific type
 func (s *Scraper) GetSourceCountByType(newsType string) int {
-	switch newsType {
-	case "global":
-		return len(s.globalSources)
-	case "ai":
-		fallthrough
-	default:
-		return len(s.aiSources)
-	}
-}
\ No newline at end of file
+	return len(s.categories.Resolve(newsType).Sources)
+}
@@ -1,115 +1,78 @@
 package scraper
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"net/http"
 	"strings"
 	"time"
 
+	"github.com/hengky/news-scrapping/internal/category"
 	"github.com/hengky/news-scrapping/pkg/models"
 	"github.com/mmcdole/gofeed"
 )
 
 // NewsSource represents a news source configuration
-type NewsSource struct {
-	Name string
-	URL  string
-	Type string // "rss" or "web"
-}
+type NewsSource = category.Source
 
-// GetAINewsSources returns AI-focused tech news sources
-func GetAINewsSources() []NewsSource {
-	return []NewsSource{
-		{
-			Name: "TechCrunch AI",
-			URL:  "https://techcrunch.com/category/artificial-intelligence/feed/",
-			Type: "rss",
-		},
-		{
-			Name: "The Verge AI",
-			URL:  "https://www.theverge.com/ai-artificial-intelligence/rss/index.xml",
-			Type: "rss",
-		},
-		{
-			Name: "AI News",
-			URL:  "https://artificialintelligence-news.com/feed/",
-			Type: "rss",
-		},
-		{
-			Name: "Bloomberg Technology",
-			URL:  "https://feeds.bloomberg.com/technology/news.rss",
-			Type: "rss",
-		},
-	}
+// ScrapeNewsFromSource scrapes news from a single source with category filtering
+func ScrapeNewsFromSource(source NewsSource, cat *category.Category) ([]models.NewsItem, error) {
+	return ScrapeNewsFromSourceWithContext(context.Background(), source, cat)
 }
 
-// GetGlobalNewsSources returns global tech/business news sources
-func GetGlobalNewsSources() []NewsSource {
-	return []NewsSource{
-		{
-			Name: "Bloomberg Technology",
-			URL:  "https://feeds.bloomberg.com/technology/news.rss",
-			Type: "rss",
-		},
-		{
-			Name: "Bloomberg Crypto",
-			URL:  "https://feeds.bloomberg.com/crypto/news.rss",
-			Type: "rss",
-		},
-		{
-			Name: "Bloomberg Politics",
-			URL:  "https://feeds.bloomberg.com/politics/news.rss",
-			Type: "rss",
-		},
-		{
-			Name: "Bloomberg Economics",
-			URL:  "https://feeds.bloomberg.com/politics/news.rss",
-			Type: "rss",
-		},
-	}
+// ScrapeNewsFromSourceWithContext scrapes news from a single source, aborting the fetch when ctx is done
+func ScrapeNewsFromSourceWithContext(ctx context.Context, source NewsSource, cat *category.Category) ([]models.NewsItem, error) {
+	news, _, err := scrapeSource(ctx, defaultFetcher(), source, cat, nil)
+	return news, err
 }
 
-// GetNewsSourcesByType returns news sources based on type
-func GetNewsSourcesByType(newsType string) []NewsSource {
-	switch newsType {
-	case "global":
-		return GetGlobalNewsSources()
-	case "ai":
-		fallthrough
-	default:
-		return GetAINewsSources()
-	}
-}
+// scrapeSource scrapes a single source and records fetch metrics for it.
+// observe, when non-nil, is called for every recent item before category filtering.
+func scrapeSource(ctx context.Context, f *fetcher, source NewsSource, cat *category.Category, observe func(models.NewsItem)) ([]models.NewsItem, models.SourceMetrics, error) {
+	metrics := models.SourceMetrics{Source: source.Name}
+	start := time.Now()
 
-// ScrapeNewsFromSource scrapes news from a single source with type filtering
-func ScrapeNewsFromSource(source NewsSource, newsType string) ([]models.NewsItem, error) {
+	var news []models.NewsItem
+	var err error
 	switch source.Type {
 	case "rss":
-		return scrapeRSSFeed(source, newsType)
+		news, err = scrapeRSSFeed(ctx, f, source, cat, observe, &metrics)
 	default:
-		return nil, fmt.Errorf("unsupported source type: %s", source.Type)
+		err = fmt.Errorf("unsupported source type: %s", source.Type)
 	}
-}
 
-// scrapeRSSFeed scrapes news from RSS feed
-func scrapeRSSFeed(source NewsSource, newsType string) ([]models.NewsItem, error) {
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	metrics.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		metrics.Error = err.Error()
 	}
+	return news, metrics, err
+}
 
-	// Create feed parser
+// scrapeRSSFeed scrapes news from RSS feed, filling in fetched/filtered/accepted counts
+func scrapeRSSFeed(ctx context.Context, f *fetcher, source NewsSource, cat *category.Category, observe func(models.NewsItem), metrics *models.SourceMetrics) ([]models.NewsItem, error) {
+	// Create feed parser using the shared client and bot User-Agent
 	fp := gofeed.NewParser()
-	fp.Client = client
+	fp.Client = f.client
+	fp.UserAgent = f.userAgent
 
 	// Parse the feed
-	feed, err := fp.ParseURL(source.URL)
+	feed, err := fp.ParseURLWithContext(source.URL, ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse RSS feed from %s: %w", source.Name, err)
 	}
 
+	newsItems := feedItems(feed, source, cat, observe, metrics)
+
+	log.Printf("Scraped %d %s articles from %s", len(newsItems), cat.Name, source.Name)
+	return newsItems, nil
+}
+
+// feedItems converts the recent items of a parsed feed that pass the category filter, up to the
+// category's per-source cap, filling in fetched/filtered/accepted counts. Observers see every
+// recent item, including those past the cap.
+func feedItems(feed *gofeed.Feed, source NewsSource, cat *category.Category, observe func(models.NewsItem), metrics *models.SourceMetrics) []models.NewsItem {
 	var newsItems []models.NewsItem
+	metrics.Fetched = len(feed.Items)
 
 	// Process recent items (last 24 hours)
 	cutoff := time.Now().Add(-24 * time.Hour)
@@ -127,20 +90,7 @@ func scrapeRSSFeed(source NewsSource, newsType string) ([]models.NewsItem, error
 
 		// Only include recent items
 		if publishedAt.Before(cutoff) {
-			continue
-		}
-
-		// Apply content filtering based on news type
-		shouldInclude := true
-		if newsType == "ai" {
-			// For AI news, filter for AI-related content
-			shouldInclude = isAIRelated(item.Title + " " + item.Description)
-		} else if newsType == "global" {
-			// For global news, use more specific filtering for markets/business/crypto
-			shouldInclude = isGlobalBusinessRelated(item.Title + " " + item.Description)
-		}
-
-		if !shouldInclude {
+			metrics.Filtered++
 			continue
 		}
 
@@ -158,119 +108,29 @@ func scrapeRSSFeed(source NewsSource, newsType string) ([]models.NewsItem, error
 			newsItem.Summary = newsItem.Summary[:297] + "..."
 		}
 
-		newsItems = append(newsItems, newsItem)
-
-		// Limit items per source based on type
-		maxItemsPerSource := 12
-		if newsType == "global" {
-			maxItemsPerSource = 8 // Reasonable limit for global news
-		}
-		
-		if len(newsItems) >= maxItemsPerSource {
-			break
+		// Let observers (e.g. the watchlist) see every recent item before category filtering
+		if observe != nil {
+			observe(newsItem)
 		}
-	}
 
-	log.Printf("Scraped %d %s articles from %s", len(newsItems), newsType, source.Name)
-	return newsItems, nil
-}
-
-// isAIRelated checks if the content is AI-related
-func isAIRelated(content string) bool {
-	content = strings.ToLower(content)
-
-	aiKeywords := []string{
-		"artificial intelligence", "ai", "machine learning", "ml",
-		"deep learning", "neural network", "chatgpt", "openai",
-		"google ai", "microsoft ai", "anthropic", "claude",
-		"generative ai", "llm", "large language model",
-		"computer vision", "natural language processing", "nlp",
-		"automation", "robotics", "algorithm", "data science",
-		"tensorflow", "pytorch", "hugging face",
-	}
-
-	for _, keyword := range aiKeywords {
-		if strings.Contains(content, keyword) {
-			return true
+		// Apply the category keyword filter
+		shouldInclude := cat.Matches(item.Title + " " + item.Description)
+		if !shouldInclude {
+			metrics.Filtered++
+			continue
 		}
-	}
-
-	return false
-}
-
-// isTechRelated checks if the content is tech-related (broader than AI)
-func isTechRelated(content string) bool {
-	content = strings.ToLower(content)
-
-	techKeywords := []string{
-		// Core tech
-		"technology", "tech", "software", "hardware", "computing", "digital",
-		"internet", "web", "mobile", "app", "platform", "cloud", "data",
-		"cyber", "security", "privacy", "blockchain", "crypto", "fintech",
-
-		// AI and related
-		"artificial intelligence", "ai", "machine learning", "ml", "automation",
-		"neural network", "algorithm", "chatgpt", "openai", "google ai",
 
-		// Business tech
-		"startup", "venture", "funding", "ipo", "acquisition", "merger",
-		"innovation", "disruption", "digital transformation", "saas",
-
-		// Companies and trends
-		"microsoft", "apple", "google", "amazon", "meta", "tesla", "nvidia",
-		"semiconductor", "chip", "processor", "quantum", "5g", "iot",
-		"virtual reality", "augmented reality", "metaverse", "gaming",
-
-		// Programming and development
-		"programming", "developer", "coding", "api", "open source",
-		"github", "database", "analytics", "big data",
-	}
-
-	for _, keyword := range techKeywords {
-		if strings.Contains(content, keyword) {
-			return true
+		// Limit items per source based on category
+		if len(newsItems) >= cat.MaxItemsPerSource {
+			metrics.Filtered++
+			continue
 		}
-	}
 
-	return false
-}
-
-// isGlobalBusinessRelated checks if content is relevant for global business/markets/crypto news
-func isGlobalBusinessRelated(content string) bool {
-	content = strings.ToLower(content)
-	
-	// More specific keywords for global business focus
-	globalKeywords := []string{
-		// Markets & Economics
-		"stock", "market", "nasdaq", "dow", "s&p", "earnings", "revenue", "profit",
-		"economy", "inflation", "recession", "gdp", "interest rate", "federal reserve",
-		"economic", "fiscal", "monetary", "trade war", "tariff",
-		
-		// Crypto & Finance
-		"bitcoin", "ethereum", "crypto", "cryptocurrency", "blockchain", "defi",
-		"fintech", "banking", "payment", "financial services", "lending",
-		
-		// Business & Corporate
-		"merger", "acquisition", "ipo", "funding", "investment", "venture capital",
-		"startup", "unicorn", "valuation", "ceo", "executive", "leadership",
-		"corporate", "business strategy", "partnerships",
-		
-		// Politics & Policy (tech related)
-		"regulation", "policy", "government", "senate", "congress", "biden",
-		"trump", "china", "trade", "sanctions", "antitrust", "monopoly",
-		
-		// Major Companies (when in business context)
-		"apple", "microsoft", "google", "amazon", "meta", "tesla", "nvidia",
-		"samsung", "tsmc", "intel", "amd",
-	}
-
-	for _, keyword := range globalKeywords {
-		if strings.Contains(content, keyword) {
-			return true
-		}
+		newsItems = append(newsItems, newsItem)
 	}
 
-	return false
+	metrics.Accepted = len(newsItems)
+	return newsItems
 }
 
 // cleanText removes HTML tags and extra whitespace
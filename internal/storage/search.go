@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// Weights of a term match in each searched field
+const (
+	titleWeight   = 3
+	sourceWeight  = 2
+	summaryWeight = 1
+)
+
+// SearchQuery selects stored articles by keywords and scrape time
+type SearchQuery struct {
+	Text     string    // Keywords; every keyword must match the title, summary, or source
+	Category string    // Empty matches all categories
+	From     time.Time // Zero means no lower bound
+	To       time.Time // Exclusive; zero means no upper bound
+	Limit    int       // 0 means no limit
+}
+
+// SearchTerms splits search text into lowercase keywords
+func SearchTerms(text string) []string {
+	return searchWords(text)
+}
+
+// searchWords splits text into lowercase words on anything other than letters and digits
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// hasWord reports whether any word starts with the term, so "fund" matches "funding"
+// but "ai" does not match "said"
+func hasWord(words []string, term string) bool {
+	for _, word := range words {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// Search returns stored articles matching every keyword, best matches first, along with the
+// total number of matches. Each result reports the dates of the digests that included it.
+func (s *Store) Search(query SearchQuery) ([]models.SearchResult, int) {
+	terms := SearchTerms(query.Text)
+	if len(terms) == 0 {
+		return nil, 0
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type match struct {
+		article *models.StoredArticle
+		score   int
+	}
+
+	var matches []match
+	for _, article := range s.articles {
+		if query.Category != "" && article.Category != query.Category {
+			continue
+		}
+		if !query.From.IsZero() && article.ScrapedAt.Before(query.From) {
+			continue
+		}
+		if !query.To.IsZero() && !article.ScrapedAt.Before(query.To) {
+			continue
+		}
+		if score := matchScore(article, terms); score > 0 {
+			matches = append(matches, match{article: article, score: score})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].article.ScrapedAt.After(matches[j].article.ScrapedAt)
+	})
+
+	total := len(matches)
+	if query.Limit > 0 && len(matches) > query.Limit {
+		matches = matches[:query.Limit]
+	}
+
+	digestDates := s.digestDatesLocked()
+	results := make([]models.SearchResult, 0, len(matches))
+	for _, m := range matches {
+		dates := digestDates[articleKey(m.article.Category, m.article.URL)]
+		results = append(results, models.SearchResult{
+			StoredArticle: *m.article,
+			InDigest:      len(dates) > 0,
+			DigestDates:   dates,
+		})
+	}
+
+	return results, total
+}
+
+// matchScore scores an article against keywords, returning 0 unless every keyword matches
+// the start of a word in the title, summary, or source
+func matchScore(article *models.StoredArticle, terms []string) int {
+	title := searchWords(article.Title)
+	summary := searchWords(article.Summary)
+	source := searchWords(article.Source)
+
+	score := 0
+	for _, term := range terms {
+		termScore := 0
+		if hasWord(title, term) {
+			termScore += titleWeight
+		}
+		if hasWord(source, term) {
+			termScore += sourceWeight
+		}
+		if hasWord(summary, term) {
+			termScore += summaryWeight
+		}
+		if termScore == 0 {
+			return 0
+		}
+		score += termScore
+	}
+	return score
+}
+
+// digestDatesLocked maps category + URL to the distinct dates of the digests that included it,
+// in ascending order. Callers must hold s.mu.
+func (s *Store) digestDatesLocked() map[string][]string {
+	dates := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, digest := range s.digests {
+		for _, item := range digest.News {
+			key := articleKey(digest.Category, item.URL)
+			if seen[key+"\n"+digest.Date] {
+				continue
+			}
+			seen[key+"\n"+digest.Date] = true
+			dates[key] = append(dates[key], digest.Date)
+		}
+	}
+	for _, list := range dates {
+		sort.Strings(list)
+	}
+	return dates
+}
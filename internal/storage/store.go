@@ -0,0 +1,497 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// File names inside the data directory
+const (
+	articlesFile = "articles.json"
+	digestsFile  = "digests.json"
+	jobsFile     = "jobs.json"
+	stateFile    = "scheduler.json"
+	websubFile   = "websub.json"
+	outboxFile   = "outbox.json"
+)
+
+// Store persists scraped articles as JSON files in a data directory
+type Store struct {
+	dir      string
+	mu       sync.RWMutex
+	articles map[string]*models.StoredArticle // keyed by category + URL
+	digests  []*models.Digest
+	jobs     []*models.JobRecord // oldest first
+	state    models.SchedulerState
+	websub   map[string]*models.WebSubSubscription // keyed by subscription ID
+	outbox   []*models.OutboxEntry                 // oldest first
+}
+
+// Open loads (or creates) a store in the given directory
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory %s: %w", dir, err)
+	}
+
+	s := &Store{
+		dir:      dir,
+		articles: make(map[string]*models.StoredArticle),
+		websub:   make(map[string]*models.WebSubSubscription),
+	}
+
+	var articles []*models.StoredArticle
+	if err := s.load(articlesFile, &articles); err != nil {
+		return nil, err
+	}
+	for _, article := range articles {
+		s.articles[articleKey(article.Category, article.URL)] = article
+	}
+
+	if err := s.load(digestsFile, &s.digests); err != nil {
+		return nil, err
+	}
+
+	if err := s.load(jobsFile, &s.jobs); err != nil {
+		return nil, err
+	}
+
+	if err := s.load(stateFile, &s.state); err != nil {
+		return nil, err
+	}
+
+	var subscriptions []*models.WebSubSubscription
+	if err := s.load(websubFile, &subscriptions); err != nil {
+		return nil, err
+	}
+	for _, sub := range subscriptions {
+		s.websub[sub.ID] = sub
+	}
+
+	if err := s.load(outboxFile, &s.outbox); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// AddArticles stores new articles for a category, skipping URLs already stored.
+// It returns the number of newly added articles.
+func (s *Store) AddArticles(category string, items []models.NewsItem) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	added := 0
+	for _, item := range items {
+		if item.URL == "" {
+			continue
+		}
+		key := articleKey(category, item.URL)
+		if _, exists := s.articles[key]; exists {
+			continue
+		}
+		s.articles[key] = &models.StoredArticle{
+			NewsItem:  item,
+			Category:  category,
+			ScrapedAt: now,
+		}
+		added++
+	}
+
+	if added == 0 {
+		return 0, nil
+	}
+	return added, s.saveArticlesLocked()
+}
+
+// ArticlesSince returns a category's articles scraped at or after since, newest published first
+func (s *Store) ArticlesSince(category string, since time.Time) []models.NewsItem {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []models.NewsItem
+	for _, article := range s.articles {
+		if article.Category != category || article.ScrapedAt.Before(since) {
+			continue
+		}
+		items = append(items, article.NewsItem)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].PublishedAt.After(items[j].PublishedAt)
+	})
+	return items
+}
+
+// StoredArticlesSince returns a category's stored articles scraped at or after since, including scrape times
+func (s *Store) StoredArticlesSince(category string, since time.Time) []models.StoredArticle {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var articles []models.StoredArticle
+	for _, article := range s.articles {
+		if article.Category != category || article.ScrapedAt.Before(since) {
+			continue
+		}
+		articles = append(articles, *article)
+	}
+	return articles
+}
+
+// ArticleCount returns the number of stored articles
+func (s *Store) ArticleCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.articles)
+}
+
+// SaveDigest stores a curated digest
+func (s *Store) SaveDigest(digest *models.Digest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.digests = append(s.digests, digest)
+	return s.save(digestsFile, s.digests)
+}
+
+// Digests returns a category's digests of the given kind created within [from, to), oldest first.
+// An empty category matches all categories.
+func (s *Store) Digests(category, kind string, from, to time.Time) []models.Digest {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var digests []models.Digest
+	for _, digest := range s.digests {
+		if category != "" && digest.Category != category {
+			continue
+		}
+		if digest.Kind != kind || digest.CreatedAt.Before(from) || !digest.CreatedAt.Before(to) {
+			continue
+		}
+		digests = append(digests, *digest)
+	}
+	return digests
+}
+
+// LatestDigest returns the most recently created digest of a category and kind.
+// A non-empty date restricts the search to digests for that day (YYYY-MM-DD).
+func (s *Store) LatestDigest(category, kind, date string) (models.Digest, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.digests) - 1; i >= 0; i-- {
+		digest := s.digests[i]
+		if digest.Category != category || digest.Kind != kind {
+			continue
+		}
+		if date != "" && digest.Date != date {
+			continue
+		}
+		return *digest, true
+	}
+	return models.Digest{}, false
+}
+
+// AddJob stores a finished job run
+func (s *Store) AddJob(record models.JobRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, &record)
+	return s.save(jobsFile, s.jobs)
+}
+
+// Jobs returns job runs newest first, optionally filtered by type, skipping offset records
+// and returning at most limit (0 for all). The total number of matching records is also returned.
+func (s *Store) Jobs(jobType string, offset, limit int) ([]models.JobRecord, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var jobs []models.JobRecord
+	total := 0
+	for i := len(s.jobs) - 1; i >= 0; i-- {
+		record := s.jobs[i]
+		if jobType != "" && record.Type != jobType {
+			continue
+		}
+		total++
+		if total <= offset || (limit > 0 && len(jobs) >= limit) {
+			continue
+		}
+		jobs = append(jobs, *record)
+	}
+	return jobs, total
+}
+
+// PruneJobs removes job runs started before the cutoff, returning how many were removed
+func (s *Store) PruneJobs(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.jobs[:0]
+	for _, record := range s.jobs {
+		if record.StartedAt.Before(before) {
+			continue
+		}
+		kept = append(kept, record)
+	}
+
+	removed := len(s.jobs) - len(kept)
+	s.jobs = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.save(jobsFile, s.jobs)
+}
+
+// PruneArticles removes articles scraped before the cutoff, returning how many were removed
+func (s *Store) PruneArticles(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for key, article := range s.articles {
+		if article.ScrapedAt.Before(before) {
+			delete(s.articles, key)
+			removed++
+		}
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.saveArticlesLocked()
+}
+
+// PruneDigests removes digests created before the cutoff, returning how many were removed
+func (s *Store) PruneDigests(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.digests[:0]
+	for _, digest := range s.digests {
+		if digest.CreatedAt.Before(before) {
+			continue
+		}
+		kept = append(kept, digest)
+	}
+
+	removed := len(s.digests) - len(kept)
+	s.digests = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.save(digestsFile, s.digests)
+}
+
+// SchedulerState returns the persisted runtime scheduler state
+func (s *Store) SchedulerState() models.SchedulerState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := s.state
+	state.Schedules = make(map[string]string, len(s.state.Schedules))
+	for name, spec := range s.state.Schedules {
+		state.Schedules[name] = spec
+	}
+	state.LastRuns = make(map[string]time.Time, len(s.state.LastRuns))
+	for name, at := range s.state.LastRuns {
+		state.LastRuns[name] = at
+	}
+	return state
+}
+
+// UpdateSchedulerState applies update to the runtime scheduler state and persists it
+func (s *Store) UpdateSchedulerState(update func(state *models.SchedulerState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state.Schedules == nil {
+		s.state.Schedules = make(map[string]string)
+	}
+	if s.state.LastRuns == nil {
+		s.state.LastRuns = make(map[string]time.Time)
+	}
+	update(&s.state)
+	return s.save(stateFile, s.state)
+}
+
+// WebSubSubscriptions returns every WebSub subscription ordered by category and source
+func (s *Store) WebSubSubscriptions() []models.WebSubSubscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscriptions := make([]models.WebSubSubscription, 0, len(s.websub))
+	for _, sub := range s.websub {
+		subscriptions = append(subscriptions, *sub)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if subscriptions[i].Category != subscriptions[j].Category {
+			return subscriptions[i].Category < subscriptions[j].Category
+		}
+		return subscriptions[i].Source < subscriptions[j].Source
+	})
+	return subscriptions
+}
+
+// WebSubSubscription returns a WebSub subscription by ID
+func (s *Store) WebSubSubscription(id string) (models.WebSubSubscription, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sub, ok := s.websub[id]
+	if !ok {
+		return models.WebSubSubscription{}, false
+	}
+	return *sub, true
+}
+
+// SaveWebSubSubscription adds or replaces a WebSub subscription
+func (s *Store) SaveWebSubSubscription(sub models.WebSubSubscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.websub[sub.ID] = &sub
+	return s.saveWebSubLocked()
+}
+
+// DeleteWebSubSubscription removes a WebSub subscription
+func (s *Store) DeleteWebSubSubscription(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.websub[id]; !ok {
+		return nil
+	}
+	delete(s.websub, id)
+	return s.saveWebSubLocked()
+}
+
+// saveWebSubLocked writes all WebSub subscriptions to disk; callers must hold s.mu
+func (s *Store) saveWebSubLocked() error {
+	subscriptions := make([]*models.WebSubSubscription, 0, len(s.websub))
+	for _, sub := range s.websub {
+		subscriptions = append(subscriptions, sub)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return s.save(websubFile, subscriptions)
+}
+
+// OutboxEntries returns every undelivered digest, oldest first
+func (s *Store) OutboxEntries() []models.OutboxEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries := make([]models.OutboxEntry, 0, len(s.outbox))
+	for _, entry := range s.outbox {
+		entries = append(entries, *entry)
+	}
+	return entries
+}
+
+// SaveOutboxEntry adds or replaces an outbox entry
+func (s *Store) SaveOutboxEntry(entry models.OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, existing := range s.outbox {
+		if existing.ID == entry.ID {
+			s.outbox[i] = &entry
+			return s.save(outboxFile, s.outbox)
+		}
+	}
+	s.outbox = append(s.outbox, &entry)
+	return s.save(outboxFile, s.outbox)
+}
+
+// DeleteOutboxEntry removes a delivered outbox entry
+func (s *Store) DeleteOutboxEntry(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, entry := range s.outbox {
+		if entry.ID == id {
+			s.outbox = append(s.outbox[:i], s.outbox[i+1:]...)
+			return s.save(outboxFile, s.outbox)
+		}
+	}
+	return nil
+}
+
+// PruneOutbox removes permanently failed deliveries created before the cutoff, returning how many were removed
+func (s *Store) PruneOutbox(before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.outbox[:0]
+	for _, entry := range s.outbox {
+		if entry.State != models.OutboxFailed || !entry.CreatedAt.Before(before) {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(s.outbox) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	s.outbox = kept
+	return removed, s.save(outboxFile, s.outbox)
+}
+
+// saveArticlesLocked writes all articles to disk; callers must hold s.mu
+func (s *Store) saveArticlesLocked() error {
+	articles := make([]*models.StoredArticle, 0, len(s.articles))
+	for _, article := range s.articles {
+		articles = append(articles, article)
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		return articles[i].ScrapedAt.Before(articles[j].ScrapedAt)
+	})
+	return s.save(articlesFile, articles)
+}
+
+// load reads a JSON file from the data directory; a missing file leaves v untouched
+func (s *Store) load(name string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// save atomically writes v as JSON to the data directory
+func (s *Store) save(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	path := filepath.Join(s.dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", name, err)
+	}
+	return nil
+}
+
+// articleKey identifies an article within a category
+func articleKey(category, url string) string {
+	return category + "|" + url
+}
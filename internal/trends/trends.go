@@ -0,0 +1,173 @@
+package trends
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/hengky/news-scrapping/internal/storage"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// Matching thresholds for deciding that two headlines cover the same story
+const (
+	minSharedWords = 3
+	minOverlap     = 0.6 // Shared words relative to the shorter headline
+	maxEarlierLink = 3
+)
+
+// stopWords are ignored when comparing headlines
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"its": true, "after": true, "over": true, "new": true, "says": true, "say": true,
+	"are": true, "was": true, "will": true, "has": true, "have": true, "this": true,
+	"that": true, "how": true, "why": true, "what": true, "now": true, "more": true,
+	"than": true, "about": true, "amid": true, "yang": true, "dan": true, "untuk": true,
+	"dari": true, "dengan": true, "ini": true, "itu": true,
+}
+
+// Tracker detects stories that keep appearing across days using the stored article pool
+type Tracker struct {
+	store    *storage.Store
+	location *time.Location
+	window   time.Duration
+}
+
+// New creates a tracker that looks back the given number of days in the store
+func New(store *storage.Store, location *time.Location, days int) *Tracker {
+	return &Tracker{
+		store:    store,
+		location: location,
+		window:   time.Duration(days) * 24 * time.Hour,
+	}
+}
+
+// Annotate sets Story on items whose story was already covered on earlier days
+func (t *Tracker) Annotate(category string, items []models.NewsItem, now time.Time) {
+	articles := t.store.StoredArticlesSince(category, now.Add(-t.window))
+	if len(articles) == 0 {
+		return
+	}
+
+	type candidate struct {
+		article models.StoredArticle
+		words   map[string]bool
+		date    time.Time
+	}
+	candidates := make([]candidate, 0, len(articles))
+	for _, article := range articles {
+		candidates = append(candidates, candidate{
+			article: article,
+			words:   headlineWords(article.Title),
+			date:    articleDate(article),
+		})
+	}
+
+	today := t.day(now)
+	for i := range items {
+		words := headlineWords(items[i].Title)
+		if len(words) < minSharedWords {
+			continue
+		}
+
+		firstSeen := today
+		sources := map[string]bool{items[i].Source: true}
+		var earlier []models.EarlierCoverage
+		for _, c := range candidates {
+			if c.article.URL == items[i].URL || !sameStory(words, c.words) {
+				continue
+			}
+			sources[c.article.Source] = true
+
+			day := t.day(c.date)
+			if !day.Before(today) {
+				continue
+			}
+			if day.Before(firstSeen) {
+				firstSeen = day
+			}
+			earlier = append(earlier, models.EarlierCoverage{
+				Title:  c.article.Title,
+				URL:    c.article.URL,
+				Source: c.article.Source,
+				Date:   c.date,
+			})
+		}
+
+		if len(earlier) == 0 {
+			continue
+		}
+
+		// Link the most recent earlier coverage first
+		sort.Slice(earlier, func(a, b int) bool {
+			return earlier[a].Date.After(earlier[b].Date)
+		})
+		if len(earlier) > maxEarlierLink {
+			earlier = earlier[:maxEarlierLink]
+		}
+
+		items[i].Story = &models.StoryContext{
+			Day:       daysBetween(firstSeen, today) + 1,
+			FirstSeen: firstSeen,
+			Sources:   len(sources),
+			Earlier:   earlier,
+		}
+	}
+}
+
+// day truncates a time to midnight in the tracker's timezone
+func (t *Tracker) day(ts time.Time) time.Time {
+	ts = ts.In(t.location)
+	return time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, t.location)
+}
+
+// daysBetween counts the calendar days from one midnight to another. The dates are compared in
+// UTC so a day lost or gained to a daylight saving change does not shift the count.
+func daysBetween(from, to time.Time) int {
+	fromUTC := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toUTC := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(toUTC.Sub(fromUTC).Hours() / 24)
+}
+
+// articleDate returns when an article was published, falling back to when it was scraped
+func articleDate(article models.StoredArticle) time.Time {
+	if !article.PublishedAt.IsZero() {
+		return article.PublishedAt
+	}
+	return article.ScrapedAt
+}
+
+// sameStory reports whether two headlines share enough significant words
+func sameStory(a, b map[string]bool) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+
+	shared := 0
+	for word := range a {
+		if b[word] {
+			shared++
+		}
+	}
+
+	shorter := len(a)
+	if len(b) < shorter {
+		shorter = len(b)
+	}
+	return shared >= minSharedWords && float64(shared)/float64(shorter) >= minOverlap
+}
+
+// headlineWords returns the significant lowercase words of a headline
+func headlineWords(title string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 3 || stopWords[word] {
+			continue
+		}
+		words[word] = true
+	}
+	return words
+}
@@ -0,0 +1,139 @@
+package watchlist
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/discord"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// alertedTTL is how long an alerted article URL is remembered to avoid duplicate alerts
+const alertedTTL = 7 * 24 * time.Hour
+
+// Watcher checks scraped articles against a keyword watchlist and sends instant alerts
+type Watcher struct {
+	terms   []string
+	discord *discord.WebhookClient
+	mu      sync.Mutex
+	alerted map[string]time.Time
+}
+
+// New creates a watcher for the given terms that alerts through the Discord client.
+// A watcher without terms matches nothing.
+func New(terms []string, discordClient *discord.WebhookClient) *Watcher {
+	return &Watcher{
+		terms:   cleanTerms(terms),
+		discord: discordClient,
+		alerted: make(map[string]time.Time),
+	}
+}
+
+// Set replaces the terms and alert client, e.g. after a configuration reload.
+// Articles already alerted are still remembered.
+func (w *Watcher) Set(terms []string, discordClient *discord.WebhookClient) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.terms = cleanTerms(terms)
+	w.discord = discordClient
+}
+
+// cleanTerms trims the terms and drops empty ones
+func cleanTerms(terms []string) []string {
+	var cleaned []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			cleaned = append(cleaned, term)
+		}
+	}
+	return cleaned
+}
+
+// Observe checks a single article and sends an alert when it matches a watchlist term.
+// It is safe for concurrent use by scraper workers.
+func (w *Watcher) Observe(item models.NewsItem) {
+	term, ok := w.Match(item)
+	if !ok {
+		return
+	}
+
+	if !w.markAlerted(item.URL) {
+		return
+	}
+
+	w.mu.Lock()
+	discordClient := w.discord
+	w.mu.Unlock()
+
+	log.Printf("Watchlist match %q: %s (%s)", term, item.Title, item.Source)
+	if err := discordClient.SendWatchlistAlert(item, term); err != nil {
+		log.Printf("Failed to send watchlist alert for %s: %v", item.URL, err)
+		w.unmarkAlerted(item.URL) // Allow a retry on the next scrape
+	}
+}
+
+// Match returns the first watchlist term matching the article. A term matches when
+// every word in it appears in the title or summary, so "OpenAI acquisition" also
+// matches "Acquisition talks put OpenAI ...". Words match at word boundaries like
+// category keywords, so "Gemini 3" does not match "Gemini ... 2023".
+func (w *Watcher) Match(item models.NewsItem) (string, bool) {
+	content := strings.ToLower(item.Title + " " + item.Summary)
+
+	for _, term := range w.Terms() {
+		words := strings.Fields(strings.ToLower(term))
+		if len(words) == 0 {
+			continue
+		}
+
+		matched := true
+		for _, word := range words {
+			if category.CountKeyword(content, word) == 0 {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return term, true
+		}
+	}
+
+	return "", false
+}
+
+// Terms returns the configured watchlist terms
+func (w *Watcher) Terms() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	terms := make([]string, len(w.terms))
+	copy(terms, w.terms)
+	return terms
+}
+
+// markAlerted records an article URL, returning false if it was already alerted
+func (w *Watcher) markAlerted(url string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	for alertedURL, at := range w.alerted {
+		if now.Sub(at) > alertedTTL {
+			delete(w.alerted, alertedURL)
+		}
+	}
+
+	if _, exists := w.alerted[url]; exists {
+		return false
+	}
+	w.alerted[url] = now
+	return true
+}
+
+// unmarkAlerted forgets an article URL after a failed alert
+func (w *Watcher) unmarkAlerted(url string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.alerted, url)
+}
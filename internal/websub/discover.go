@@ -0,0 +1,130 @@
+package websub
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxDiscoveryBytes caps how much of a feed is read when looking for its hub
+const maxDiscoveryBytes = 1 << 20
+
+// discover fetches a feed and returns the hub and topic it advertises, from Link headers or
+// <link rel="hub"> / <atom:link rel="hub"> elements. The hub is empty when the feed has none.
+func (m *Manager) discover(ctx context.Context, feedURL string) (hub, topic string, err error) {
+	resp, err := m.fetcher.Fetch(ctx, feedURL)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to fetch feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("feed returned status %d", resp.StatusCode)
+	}
+
+	hub, self := linkHeaders(resp.Header)
+	if hub == "" {
+		hub, self = feedLinks(io.LimitReader(resp.Body, maxDiscoveryBytes))
+	}
+	if hub == "" {
+		return "", "", nil
+	}
+
+	base := resp.Request.URL
+	hub = resolve(base, hub)
+	topic = feedURL
+	if self != "" {
+		topic = resolve(base, self)
+	}
+	return hub, topic, nil
+}
+
+// linkHeaders returns the hub and self URLs from HTTP Link headers
+func linkHeaders(header http.Header) (hub, self string) {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			for _, param := range parts[1:] {
+				name, rel, found := strings.Cut(strings.TrimSpace(param), "=")
+				if !found || !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for _, r := range strings.Fields(strings.Trim(rel, `"`)) {
+					switch strings.ToLower(r) {
+					case "hub":
+						if hub == "" {
+							hub = target
+						}
+					case "self":
+						if self == "" {
+							self = target
+						}
+					}
+				}
+			}
+		}
+	}
+	return hub, self
+}
+
+// feedLinks scans the feed header for hub and self links, stopping at the first item
+func feedLinks(body io.Reader) (hub, self string) {
+	decoder := xml.NewDecoder(body)
+	decoder.Strict = false
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil // Only ASCII attribute values are needed
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return hub, self
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "item", "entry":
+			return hub, self
+		case "link":
+			var rel, href string
+			for _, attr := range start.Attr {
+				switch attr.Name.Local {
+				case "rel":
+					rel = attr.Value
+				case "href":
+					href = attr.Value
+				}
+			}
+			for _, r := range strings.Fields(rel) {
+				switch strings.ToLower(r) {
+				case "hub":
+					if hub == "" {
+						hub = href
+					}
+				case "self":
+					if self == "" {
+						self = href
+					}
+				}
+			}
+		}
+	}
+}
+
+// resolve resolves a possibly relative link against the feed URL
+func resolve(base *url.URL, link string) string {
+	ref, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(ref).String()
+}
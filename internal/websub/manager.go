@@ -0,0 +1,430 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hengky/news-scrapping/internal/category"
+	"github.com/hengky/news-scrapping/internal/config"
+	"github.com/hengky/news-scrapping/internal/storage"
+	"github.com/hengky/news-scrapping/pkg/models"
+)
+
+// CallbackPath is the path hubs call back on, followed by the subscription ID
+const CallbackPath = "/api/v1/websub/callback/"
+
+// MaxNotificationBytes caps the size of a pushed feed document
+const MaxNotificationBytes = 2 << 20
+
+// Subscription timing
+const (
+	renewBefore    = 24 * time.Hour // Renew active subscriptions this long before the lease expires
+	retryAfter     = 24 * time.Hour // Check feeds without a hub, and failed subscriptions, again after this long
+	pendingTimeout = time.Hour      // Request subscriptions the hub has not verified again after this long
+)
+
+var (
+	// ErrUnknownSubscription is returned for callbacks that match no subscription
+	ErrUnknownSubscription = errors.New("unknown WebSub subscription")
+	// ErrInvalidSignature is returned for notifications without a valid X-Hub-Signature
+	ErrInvalidSignature = errors.New("invalid WebSub signature")
+)
+
+// Fetcher fetches feeds for hub discovery
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*http.Response, error)
+}
+
+// IngestFunc adds the items of a pushed feed document to the article pool, returning how many were new
+type IngestFunc func(cat *category.Category, source category.Source, body []byte) (int, error)
+
+// Manager subscribes to the WebSub hubs of RSS sources, verifies subscription callbacks,
+// and feeds pushed items into the article pool
+type Manager struct {
+	callbackBase string
+	leaseSeconds int
+	categories   *category.Registry
+	store        *storage.Store
+	fetcher      Fetcher
+	ingest       IngestFunc
+	client       *http.Client
+	syncMu       sync.Mutex // Serializes Sync runs
+	mu           sync.Mutex // Serializes subscription updates
+}
+
+// New creates a subscription manager for the public base URL in WEBSUB_CALLBACK_URL
+func New(cfg *config.Config, categories *category.Registry, store *storage.Store, fetcher Fetcher, ingest IngestFunc) *Manager {
+	return &Manager{
+		callbackBase: strings.TrimRight(cfg.WebSubCallbackURL, "/"),
+		leaseSeconds: cfg.WebSubLeaseSeconds,
+		categories:   categories,
+		store:        store,
+		fetcher:      fetcher,
+		ingest:       ingest,
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Subscriptions returns every subscription, including feeds without a hub
+func (m *Manager) Subscriptions() []models.WebSubSubscription {
+	return m.store.WebSubSubscriptions()
+}
+
+// Sync subscribes to the hubs of RSS sources that are not subscribed yet, renews leases that are
+// about to expire, and unsubscribes from sources that were removed
+func (m *Manager) Sync(ctx context.Context) {
+	m.syncMu.Lock()
+	defer m.syncMu.Unlock()
+
+	existing := make(map[string]models.WebSubSubscription)
+	for _, sub := range m.store.WebSubSubscriptions() {
+		existing[subscriptionKey(sub.Category, sub.SourceURL)] = sub
+	}
+
+	now := time.Now()
+	for _, cat := range m.categories.All() {
+		for _, source := range cat.Sources {
+			if source.Type != "rss" {
+				continue
+			}
+
+			key := subscriptionKey(cat.Name, source.URL)
+			sub, ok := existing[key]
+			delete(existing, key)
+
+			if !ok {
+				sub = models.WebSubSubscription{
+					ID:        randomHex(16),
+					Category:  cat.Name,
+					Source:    source.Name,
+					SourceURL: source.URL,
+					Secret:    randomHex(32),
+				}
+				m.subscribe(ctx, sub, true)
+				continue
+			}
+
+			switch sub.State {
+			case models.WebSubActive:
+				if sub.ExpiresAt != nil && sub.ExpiresAt.Sub(now) < renewBefore {
+					m.subscribe(ctx, sub, false)
+				}
+			case models.WebSubPending:
+				if now.Sub(sub.CheckedAt) > pendingTimeout {
+					m.subscribe(ctx, sub, false)
+				}
+			case models.WebSubUnsubscribing:
+				// The source was added back before the hub confirmed the unsubscribe
+				m.subscribe(ctx, sub, false)
+			default:
+				if now.Sub(sub.CheckedAt) > retryAfter {
+					m.subscribe(ctx, sub, true)
+				}
+			}
+		}
+	}
+
+	// Whatever is left belongs to sources that were removed
+	for _, sub := range existing {
+		m.unsubscribe(ctx, sub)
+	}
+}
+
+// subscribe requests a subscription (or renewal) from the hub, discovering the hub first when needed
+func (m *Manager) subscribe(ctx context.Context, sub models.WebSubSubscription, discover bool) {
+	sub.CheckedAt = time.Now()
+	sub.Error = ""
+
+	if discover || sub.Hub == "" {
+		hub, topic, err := m.discover(ctx, sub.SourceURL)
+		if err != nil {
+			sub.State = models.WebSubFailed
+			sub.Error = err.Error()
+			m.save(sub)
+			log.Printf("Warning: WebSub discovery for %s failed: %v", sub.Source, err)
+			return
+		}
+		if hub == "" {
+			sub.State = models.WebSubUnsupported
+			m.save(sub)
+			return
+		}
+		sub.Hub, sub.Topic = hub, topic
+	}
+
+	// Keep accepting pushes while an active lease is renewed
+	if sub.State != models.WebSubActive {
+		sub.State = models.WebSubPending
+	}
+
+	// Save before asking the hub, which may verify the callback before it responds
+	m.save(sub)
+
+	if err := m.request(ctx, sub, "subscribe"); err != nil {
+		m.update(sub.ID, func(current *models.WebSubSubscription) {
+			if current.State == models.WebSubPending {
+				current.State = models.WebSubFailed
+			}
+			current.Error = err.Error()
+		})
+		log.Printf("Warning: WebSub subscription for %s failed: %v", sub.Source, err)
+		return
+	}
+
+	log.Printf("Requested WebSub subscription for %s (%s) from %s", sub.Source, sub.Category, sub.Hub)
+}
+
+// unsubscribe asks the hub to stop pushing a removed source; subscriptions without a hub are dropped
+func (m *Manager) unsubscribe(ctx context.Context, sub models.WebSubSubscription) {
+	switch sub.State {
+	case models.WebSubActive, models.WebSubPending:
+	case models.WebSubUnsubscribing:
+		// Give up waiting for the hub to confirm, the lease expires on its own
+		if time.Since(sub.CheckedAt) > retryAfter {
+			m.delete(sub.ID)
+		}
+		return
+	default:
+		m.delete(sub.ID)
+		return
+	}
+
+	sub.State = models.WebSubUnsubscribing
+	sub.CheckedAt = time.Now()
+	m.save(sub)
+
+	if err := m.request(ctx, sub, "unsubscribe"); err != nil {
+		log.Printf("Warning: WebSub unsubscribe for %s failed: %v", sub.Source, err)
+		return
+	}
+	log.Printf("Requested WebSub unsubscribe for removed source %s (%s)", sub.Source, sub.Category)
+}
+
+// request sends a subscribe or unsubscribe request to the hub
+func (m *Manager) request(ctx context.Context, sub models.WebSubSubscription, mode string) error {
+	form := url.Values{
+		"hub.callback": {m.callbackBase + CallbackPath + sub.ID},
+		"hub.mode":     {mode},
+		"hub.topic":    {sub.Topic},
+	}
+	if mode == "subscribe" {
+		form.Set("hub.lease_seconds", strconv.Itoa(m.leaseSeconds))
+		form.Set("hub.secret", sub.Secret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create hub request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach hub %s: %w", sub.Hub, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("hub %s returned status %d: %s", sub.Hub, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Verify answers a hub's verification of intent, returning the challenge to echo back.
+// Denials are recorded and return an empty challenge.
+func (m *Manager) Verify(id string, query url.Values) (string, error) {
+	sub, ok := m.store.WebSubSubscription(id)
+	if !ok {
+		return "", ErrUnknownSubscription
+	}
+
+	mode := query.Get("hub.mode")
+	if mode == "denied" {
+		m.update(id, func(current *models.WebSubSubscription) {
+			current.State = models.WebSubDenied
+			current.Error = query.Get("hub.reason")
+		})
+		log.Printf("Warning: WebSub hub denied the subscription for %s: %s", sub.Source, query.Get("hub.reason"))
+		return "", nil
+	}
+
+	challenge := query.Get("hub.challenge")
+	if challenge == "" || query.Get("hub.topic") != sub.Topic {
+		return "", fmt.Errorf("%w: topic or challenge does not match", ErrUnknownSubscription)
+	}
+
+	switch mode {
+	case "subscribe":
+		if sub.State != models.WebSubPending && sub.State != models.WebSubActive {
+			return "", fmt.Errorf("%w: no subscription was requested", ErrUnknownSubscription)
+		}
+		lease, err := strconv.Atoi(query.Get("hub.lease_seconds"))
+		if err != nil || lease <= 0 {
+			lease = m.leaseSeconds
+		}
+		m.update(id, func(current *models.WebSubSubscription) {
+			expires := time.Now().Add(time.Duration(lease) * time.Second)
+			current.State = models.WebSubActive
+			current.LeaseSeconds = lease
+			current.ExpiresAt = &expires
+			current.Error = ""
+		})
+		log.Printf("WebSub subscription for %s (%s) verified for %v", sub.Source, sub.Category, time.Duration(lease)*time.Second)
+	case "unsubscribe":
+		if sub.State != models.WebSubUnsubscribing {
+			return "", fmt.Errorf("%w: no unsubscribe was requested", ErrUnknownSubscription)
+		}
+		m.delete(id)
+		log.Printf("WebSub subscription for %s (%s) removed", sub.Source, sub.Category)
+	default:
+		return "", fmt.Errorf("unsupported hub.mode %q", mode)
+	}
+
+	return challenge, nil
+}
+
+// Notify verifies a pushed feed document against the subscription secret and adds its items
+// to the article pool, returning how many articles were new
+func (m *Manager) Notify(id string, body []byte, signature string) (int, error) {
+	sub, ok := m.store.WebSubSubscription(id)
+	if !ok || sub.State == models.WebSubUnsubscribing {
+		return 0, ErrUnknownSubscription
+	}
+
+	if !validSignature(sub.Secret, body, signature) {
+		return 0, ErrInvalidSignature
+	}
+
+	cat, ok := m.categories.Get(sub.Category)
+	if !ok {
+		return 0, fmt.Errorf("%w: category %s was removed", ErrUnknownSubscription, sub.Category)
+	}
+	var source category.Source
+	found := false
+	for _, candidate := range cat.Sources {
+		if candidate.URL == sub.SourceURL {
+			source, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return 0, fmt.Errorf("%w: source %s was removed", ErrUnknownSubscription, sub.Source)
+	}
+
+	added, err := m.ingest(cat, source, body)
+	if err != nil {
+		return 0, err
+	}
+
+	m.update(id, func(current *models.WebSubSubscription) {
+		now := time.Now()
+		current.LastNotification = &now
+		current.Notifications++
+	})
+
+	log.Printf("WebSub push from %s (%s): %d new articles", sub.Source, sub.Category, added)
+	return added, nil
+}
+
+// validSignature checks an X-Hub-Signature header such as sha256=<hex> against the secret
+func validSignature(secret string, body []byte, header string) bool {
+	method, signature, found := strings.Cut(header, "=")
+	if !found {
+		return false
+	}
+
+	var newHash func() hash.Hash
+	switch strings.ToLower(method) {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha384":
+		newHash = sha512.New384
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return false
+	}
+
+	provided, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(provided, mac.Sum(nil))
+}
+
+// save stores a subscription, keeping notifications recorded since it was read
+func (m *Manager) save(sub models.WebSubSubscription) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if current, ok := m.store.WebSubSubscription(sub.ID); ok {
+		sub.LastNotification = current.LastNotification
+		sub.Notifications = current.Notifications
+	}
+	if err := m.store.SaveWebSubSubscription(sub); err != nil {
+		log.Printf("Failed to store WebSub subscription for %s: %v", sub.Source, err)
+	}
+}
+
+// update applies a change to the stored copy of a subscription, if it still exists
+func (m *Manager) update(id string, change func(sub *models.WebSubSubscription)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sub, ok := m.store.WebSubSubscription(id)
+	if !ok {
+		return
+	}
+	change(&sub)
+	if err := m.store.SaveWebSubSubscription(sub); err != nil {
+		log.Printf("Failed to store WebSub subscription for %s: %v", sub.Source, err)
+	}
+}
+
+// delete removes a subscription, logging storage errors
+func (m *Manager) delete(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.store.DeleteWebSubSubscription(id); err != nil {
+		log.Printf("Failed to delete WebSub subscription %s: %v", id, err)
+	}
+}
+
+// subscriptionKey identifies the subscription of a source within a category
+func subscriptionKey(category, sourceURL string) string {
+	return category + "|" + sourceURL
+}
+
+// randomHex returns n random bytes, hex-encoded
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to read random bytes: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"flag"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,25 +13,54 @@ import (
 	"time"
 
 	"github.com/hengky/news-scrapping/internal/api"
+	"github.com/hengky/news-scrapping/internal/category"
 	"github.com/hengky/news-scrapping/internal/config"
+	"github.com/hengky/news-scrapping/internal/grpcapi"
 	"github.com/hengky/news-scrapping/internal/scheduler"
+	"github.com/hengky/news-scrapping/internal/storage"
+	"github.com/hengky/news-scrapping/pkg/models"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	once := flag.Bool("once", false, "Run the scrape, curate, and deliver pipeline once and exit instead of starting the server")
+	newsType := flag.String("type", "", "News category to run with -once (default: every category)")
+	flag.Parse()
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
-	// Initialize router
-	router := api.SetupRouter(cfg)
+	// Load news categories (built-in plus CATEGORIES_FILE)
+	categories, err := category.NewRegistry(cfg)
+	if err != nil {
+		log.Fatalf("Failed to load news categories: %v", err)
+	}
+
+	// Open the article store
+	store, err := storage.Open(cfg.DataDir)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
 
 	// Initialize scheduler
-	scheduler := scheduler.New(cfg)
+	scheduler := scheduler.New(cfg, categories, store)
+
+	// One-shot mode for cron-style runners (GitHub Actions, Cloud Run Jobs)
+	if *once {
+		code := runOnce(scheduler, categories, *newsType)
+		scheduler.Stop()
+		os.Exit(code)
+	}
+
 	scheduler.Start()
 	defer scheduler.Stop()
 
+	// Initialize router (shares the scheduler so status reflects scheduled runs)
+	router := api.SetupRouter(cfg, scheduler)
+
 	// Setup server
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -43,6 +75,37 @@ func main() {
 		}
 	}()
 
+	// Serve the gRPC API alongside REST for internal services
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC on port %s: %v", cfg.GRPCPort, err)
+		}
+		if cfg.AdminAPIKey == "" {
+			log.Printf("Warning: GRPC_PORT is set without ADMIN_API_KEY, gRPC calls will be rejected")
+		}
+		grpcServer = grpcapi.NewServer(scheduler)
+		go func() {
+			log.Printf("Starting gRPC server on port %s", cfg.GRPCPort)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
+	// Reload configuration, sources, and schedules on SIGHUP without restarting
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			log.Println("Received SIGHUP, reloading configuration...")
+			if err := scheduler.Reload(); err != nil {
+				log.Printf("Reload failed, keeping the current configuration: %v", err)
+			}
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -55,6 +118,63 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	if grpcServer != nil {
+		// Progress streams stay open until clients disconnect, so stop them after the timeout
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}
 
 	log.Println("Server exited")
-}
\ No newline at end of file
+}
+
+// runOnce runs the news pipeline for one or every category, prints a summary, and returns
+// the process exit code: 0 on success, 1 if any category failed, 2 for an unknown category.
+func runOnce(sched *scheduler.Scheduler, categories *category.Registry, newsType string) int {
+	names := categories.Names()
+	if newsType != "" {
+		cat, ok := categories.Get(newsType)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown news type %q, available: %v\n", newsType, categories.Names())
+			return 2
+		}
+		names = []string{cat.Name}
+	}
+
+	exitCode := 0
+	fmt.Println("News pipeline summary:")
+	for _, name := range names {
+		err := sched.RunJobByType(name, models.TriggerCLI)
+
+		history := sched.GetJobHistory()
+		if len(history) == 0 || history[0].Type != name {
+			fmt.Printf("  %-10s failed   %v\n", name, err)
+			exitCode = 1
+			continue
+		}
+
+		record := history[0]
+		line := fmt.Sprintf("  %-10s %-8s %d items in %v", name, record.Status, record.NewsCount,
+			time.Duration(record.DurationMs)*time.Millisecond)
+		if record.Error != "" {
+			line += " - " + record.Error
+		} else if err != nil {
+			line += " - " + err.Error()
+		}
+		fmt.Println(line)
+
+		// A queued digest is only retried while the service runs, so it counts as a failure here
+		if err != nil || record.Status == "failed" || record.Status == "queued" {
+			exitCode = 1
+		}
+	}
+
+	return exitCode
+}
@@ -4,18 +4,53 @@ import "time"
 
 // NewsItem represents a single news article
 type NewsItem struct {
-	Title     string `json:"title"`
-	Summary   string `json:"summary"`
-	URL       string `json:"url"`
-	Source    string `json:"source"`
-	Relevance string `json:"relevance,omitempty"`
-	PublishedAt time.Time `json:"published_at,omitempty"`
+	Title          string        `json:"title"`
+	Summary        string        `json:"summary"`
+	URL            string        `json:"url"`
+	Source         string        `json:"source"`
+	Relevance      string        `json:"relevance,omitempty"`
+	Sentiment      string        `json:"sentiment,omitempty"`       // "positive", "negative", or "neutral"
+	Impact         string        `json:"impact,omitempty"`          // "low", "medium", or "high"
+	RelevanceScore *int          `json:"relevance_score,omitempty"` // 0-100, nil when the AI did not score the item
+	PublishedAt    time.Time     `json:"published_at,omitempty"`
+	Story          *StoryContext `json:"story,omitempty"`
 }
 
+// StoryContext marks a news item as part of a story covered on earlier days
+type StoryContext struct {
+	Day       int               `json:"day"` // 1 is the day the story first appeared
+	FirstSeen time.Time         `json:"first_seen"`
+	Sources   int               `json:"sources"` // Distinct sources covering the story
+	Earlier   []EarlierCoverage `json:"earlier,omitempty"`
+}
+
+// EarlierCoverage links to an earlier article about the same story
+type EarlierCoverage struct {
+	Title  string    `json:"title"`
+	URL    string    `json:"url"`
+	Source string    `json:"source"`
+	Date   time.Time `json:"date"`
+}
+
+// Sentiment values assigned by the AI
+const (
+	SentimentPositive = "positive"
+	SentimentNegative = "negative"
+	SentimentNeutral  = "neutral"
+)
+
+// Impact levels assigned by the AI
+const (
+	ImpactLow    = "low"
+	ImpactMedium = "medium"
+	ImpactHigh   = "high"
+)
+
 // NewsResponse represents the response from Gemini AI
 type NewsResponse struct {
 	News       []NewsItem  `json:"news"`
 	TokenUsage *TokenUsage `json:"token_usage,omitempty"`
+	Cached     bool        `json:"cached,omitempty"` // Served from the curation cache without a Gemini call
 }
 
 // TokenUsage represents token usage statistics from AI processing
@@ -27,16 +62,189 @@ type TokenUsage struct {
 
 // JobStatus represents the status of a news scraping job
 type JobStatus struct {
-	LastRun    time.Time `json:"last_run"`
-	Status     string    `json:"status"`
-	NewsCount  int       `json:"news_count"`
-	NextRun    string    `json:"next_run"`
-	Error      string    `json:"error,omitempty"`
+	LastRun   time.Time       `json:"last_run"`
+	Status    string          `json:"status"`
+	NewsCount int             `json:"news_count"`
+	NextRun   string          `json:"next_run"`
+	Error     string          `json:"error,omitempty"`
+	Sources   []SourceMetrics `json:"sources,omitempty"`
+	Paused    bool            `json:"paused"`
+}
+
+// SchedulerStatus is the status of the most recent job plus the independent status of every job type
+type SchedulerStatus struct {
+	JobStatus
+	Types map[string]JobStatus `json:"types"`
+}
+
+// SchedulerState is the runtime scheduler configuration changed through the admin API
+type SchedulerState struct {
+	Paused    bool                 `json:"paused"`
+	PausedAt  *time.Time           `json:"paused_at,omitempty"`
+	Schedules map[string]string    `json:"schedules,omitempty"` // Cron overrides keyed by job name
+	LastRuns  map[string]time.Time `json:"last_runs,omitempty"` // When each scheduled job last fired
+}
+
+// ScheduledJob describes a cron job that can be rescheduled through the admin API
+type ScheduledJob struct {
+	Name            string `json:"name"`
+	Schedule        string `json:"schedule"`
+	DefaultSchedule string `json:"default_schedule"`
+	NextRun         string `json:"next_run,omitempty"`
+}
+
+// StoredArticle is a scraped article kept in the article pool
+type StoredArticle struct {
+	NewsItem
+	Category  string    `json:"category"`
+	ScrapedAt time.Time `json:"scraped_at"`
+}
+
+// SearchResult is a stored article matching a search, with whether it made a digest
+type SearchResult struct {
+	StoredArticle
+	InDigest    bool     `json:"in_digest"`
+	DigestDates []string `json:"digest_dates,omitempty"` // Dates of the digests that included the article
+}
+
+// Digest is a curated set of news items produced for a category
+type Digest struct {
+	ID         string      `json:"id"`
+	Category   string      `json:"category"`
+	Kind       string      `json:"kind"` // "daily" or "weekly"
+	Date       string      `json:"date"` // YYYY-MM-DD in the configured timezone
+	CreatedAt  time.Time   `json:"created_at"`
+	News       []NewsItem  `json:"news"`
+	TokenUsage *TokenUsage `json:"token_usage,omitempty"`
+}
+
+// Digest kinds
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// OutboxEntry is a curated digest whose delivery failed, kept for the retry worker
+type OutboxEntry struct {
+	ID          string           `json:"id"`
+	JobID       string           `json:"job_id"`
+	Category    string           `json:"category"`
+	Kind        string           `json:"kind"` // "daily" or "weekly"
+	CreatedAt   time.Time        `json:"created_at"`
+	News        []NewsItem       `json:"news"`
+	TokenUsage  *TokenUsage      `json:"token_usage,omitempty"`
+	State       string           `json:"state"`
+	Attempts    int              `json:"attempts"`
+	NextAttempt time.Time        `json:"next_attempt"`
+	LastError   string           `json:"last_error,omitempty"`
+	FailedAt    *time.Time       `json:"failed_at,omitempty"`
+	Progress    DeliveryProgress `json:"progress"` // What earlier attempts already posted
+}
+
+// DeliveryProgress records how much of a digest reached Discord, so a retry resumes where the
+// last attempt stopped instead of posting the digest again
+type DeliveryProgress struct {
+	SentMessages int    `json:"sent_messages"`
+	ThreadID     string `json:"thread_id,omitempty"` // Thread or forum post holding the digest
 }
 
+// Outbox entry states
+const (
+	OutboxPending = "pending" // Waiting for the next delivery attempt
+	OutboxFailed  = "failed"  // Not delivered within OUTBOX_MAX_AGE_HOURS; no more attempts
+)
+
+// SourceMetrics captures how a single source performed during a scraping run
+type SourceMetrics struct {
+	Source     string `json:"source"`
+	Fetched    int    `json:"fetched"`
+	Filtered   int    `json:"filtered"`
+	Accepted   int    `json:"accepted"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// JobRecord represents a single execution of a news job
+type JobRecord struct {
+	ID           string          `json:"id"`
+	Type         string          `json:"type"`
+	Trigger      string          `json:"trigger"`
+	StartedAt    time.Time       `json:"started_at"`
+	FinishedAt   time.Time       `json:"finished_at"`
+	DurationMs   int64           `json:"duration_ms"`
+	Status       string          `json:"status"`
+	ScrapedCount int             `json:"scraped_count"`
+	NewsCount    int             `json:"news_count"`
+	TokenUsage   *TokenUsage     `json:"token_usage,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	Sources      []SourceMetrics `json:"sources,omitempty"`
+}
+
+// WebSubSubscription is a push subscription to a feed through its WebSub hub
+type WebSubSubscription struct {
+	ID               string     `json:"id"` // Random token used in the callback URL
+	Category         string     `json:"category"`
+	Source           string     `json:"source"`
+	SourceURL        string     `json:"source_url"`
+	Topic            string     `json:"topic,omitempty"` // Feed URL advertised as rel="self", or the source URL
+	Hub              string     `json:"hub,omitempty"`
+	Secret           string     `json:"secret,omitempty"` // HMAC secret shared with the hub
+	State            string     `json:"state"`
+	LeaseSeconds     int        `json:"lease_seconds,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	CheckedAt        time.Time  `json:"checked_at"` // Last discovery or subscription request
+	LastNotification *time.Time `json:"last_notification,omitempty"`
+	Notifications    int        `json:"notifications"`
+	Error            string     `json:"error,omitempty"`
+}
+
+// WebSub subscription states
+const (
+	WebSubPending       = "pending"       // Subscription requested, waiting for the hub to verify it
+	WebSubActive        = "active"        // Verified by the hub, pushes are accepted
+	WebSubDenied        = "denied"        // Rejected by the hub
+	WebSubUnsupported   = "unsupported"   // The feed advertises no hub
+	WebSubFailed        = "failed"        // Discovery or the subscription request failed
+	WebSubUnsubscribing = "unsubscribing" // Unsubscribe requested after the source was removed
+)
+
+// JobProgress is an event published as a job moves through the pipeline
+type JobProgress struct {
+	JobID        string    `json:"job_id"`
+	Type         string    `json:"type"`
+	Trigger      string    `json:"trigger"`
+	Stage        string    `json:"stage"`
+	Message      string    `json:"message,omitempty"`
+	ScrapedCount int       `json:"scraped_count"`
+	NewsCount    int       `json:"news_count"`
+	Status       string    `json:"status,omitempty"` // Final status, set on the finished stage
+	Error        string    `json:"error,omitempty"`
+	Time         time.Time `json:"time"`
+}
+
+// Job progress stages, in pipeline order
+const (
+	StageStarted    = "started"
+	StageScraping   = "scraping"
+	StageCurating   = "curating"
+	StageDelivering = "delivering"
+	StageFinished   = "finished"
+)
+
+// Job triggers, recording what started a job run
+const (
+	TriggerScheduled = "scheduled"
+	TriggerManual    = "manual"
+	TriggerCLI       = "cli"
+	TriggerStartup   = "startup"
+	TriggerCatchUp   = "catch-up"
+	TriggerHook      = "hook"
+	TriggerGRPC      = "grpc"
+)
+
 // APIResponse represents a standard API response
 type APIResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
-}
\ No newline at end of file
+}